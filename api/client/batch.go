@@ -0,0 +1,173 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"sync"
+	"time"
+)
+
+// batchResult is the result of a batch, sent to every caller with edits in it
+type batchResult struct {
+	status payload.YangPatchStatus
+	err    error
+}
+
+// batch collects the edits to one mounted device until it is flushed
+type batch struct {
+	edits   []payload.YangPatchEdit
+	waiters []chan batchResult
+}
+
+// batcher groups the writes to each mounted device that arrive within a window into
+// a single YANG-Patch, so they are committed on the device in one transaction. The window
+// starts with the first write to a device, writes arriving after it go into the next batch.
+// Each write blocks until its batch is sent, so a write that waits on another one to succeed,
+// like the resources terraform creates after the ones they reference, never shares its batch
+type batcher struct {
+	window time.Duration
+
+	mutex   sync.Mutex
+	batches map[string]*batch
+	patches int
+}
+
+// WithBatchWindow enables batching, writes below a mount are held for the window and sent
+// to the device together with the other writes to it that arrive within it, 0 disables batching.
+// It saves commits on the device, it doesn't make writes arriving in different windows atomic
+func WithBatchWindow(window time.Duration) Option {
+	return func(c *Client) {
+		if window > 0 {
+			c.batcher = &batcher{
+				window:  window,
+				batches: map[string]*batch{},
+			}
+		}
+	}
+}
+
+// batchWrite adds a single edit to the batch of its device and blocks until the batch is sent,
+// the write is not batched when batching is disabled or the url is not below a mount
+func (c *Client) batchWrite(url string, patch *payload.YangPatch) (batched bool, err error) {
+	if c.batcher == nil {
+		return false, nil
+	}
+	mountURL, target, ok := payload.SplitMountURL(url)
+	if !ok {
+		return false, nil
+	}
+
+	for i := range patch.Edit {
+		patch.Edit[i].Target = target + patch.Edit[i].Target
+	}
+	_, err = c.batchPatch(mountURL, patch)
+	return true, err
+}
+
+// batchPatch adds the edits of a patch to the batch of a mount and blocks until the batch is sent,
+// the edits are renumbered so they are unique in the batch
+func (c *Client) batchPatch(mountURL string, patch *payload.YangPatch) (payload.YangPatchStatus, error) {
+	done := make(chan batchResult, 1)
+
+	b := c.batcher
+	b.mutex.Lock()
+	pending, found := b.batches[mountURL]
+	if !found {
+		pending = &batch{}
+		b.batches[mountURL] = pending
+		time.AfterFunc(b.window, func() { c.flush(mountURL) })
+	}
+	editIDs := []string{}
+	for _, edit := range patch.Edit {
+		edit.EditID = fmt.Sprintf("edit-%d", len(pending.edits)+1)
+		pending.edits = append(pending.edits, edit)
+		editIDs = append(editIDs, edit.EditID)
+	}
+	pending.waiters = append(pending.waiters, done)
+	b.mutex.Unlock()
+
+	result := <-done
+	status := callerStatus(result.status, patch.Edit, editIDs)
+	if result.err == nil {
+		return status, nil
+	}
+
+	// The batch is one transaction so none of its edits were applied when one failed, only the
+	// caller whose edit failed gets its error, ie ErrAlreadyExists, the others may retry their edits
+	if !causedBatchFailure(result.status, patch.Edit, editIDs) && (result.status.EditStatus != nil || errors.Is(result.err, ErrAlreadyExists)) {
+		return status, fmt.Errorf("another edit in the batch failed: %v", result.err)
+	}
+	if editErr := status.Err(); editErr != nil && result.status.EditStatus != nil {
+		return status, fmt.Errorf("%v: %w", editErr, result.err)
+	}
+	return status, result.err
+}
+
+// callerStatus returns the status of a caller's edits in a batch by the edit-ids of its own patch,
+// the status is unchanged when it doesn't list the edits
+func callerStatus(status payload.YangPatchStatus, edits []payload.YangPatchEdit, editIDs []string) payload.YangPatchStatus {
+	if status.EditStatus == nil {
+		return status
+	}
+	results := map[string]payload.YangPatchEditResult{}
+	for _, result := range status.EditStatus.Edit {
+		results[result.EditID] = result
+	}
+
+	caller := status
+	caller.EditStatus = &payload.YangPatchEditStatus{}
+	for i, editID := range editIDs {
+		if result, ok := results[editID]; ok {
+			result.EditID = edits[i].EditID
+			caller.EditStatus.Edit = append(caller.EditStatus.Edit, result)
+		}
+	}
+	return caller
+}
+
+// causedBatchFailure reports whether any of a caller's edits failed, when the status
+// doesn't list the edits any create edit is assumed to have failed
+func causedBatchFailure(status payload.YangPatchStatus, edits []payload.YangPatchEdit, editIDs []string) bool {
+	if status.EditStatus == nil {
+		for _, edit := range edits {
+			if edit.Operation == "create" {
+				return true
+			}
+		}
+		return false
+	}
+	for _, editID := range editIDs {
+		if status.EditErr(editID) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// flush sends the batch of a device as one YANG-Patch, every caller gets the result of the whole batch
+func (c *Client) flush(mountURL string) {
+	b := c.batcher
+	b.mutex.Lock()
+	pending := b.batches[mountURL]
+	delete(b.batches, mountURL)
+	b.patches++
+	patch := payload.NewYangPatch(fmt.Sprintf("terraform-%d", b.patches))
+	b.mutex.Unlock()
+
+	patch.Comment = fmt.Sprintf("%d edits batched by terraform", len(pending.edits))
+	for _, edit := range pending.edits {
+		patch.Append(edit)
+	}
+
+	log.Printf("[DEBUG] Flushing %s with %d edits to %s", patch.PatchID, len(patch.Edit), mountURL)
+
+	status, err := c.sendYangPatch(mountURL, patch, nil)
+	if err != nil {
+		err = fmt.Errorf("batch of %d edits to %s: %w", len(patch.Edit), mountURL, err)
+	}
+	for _, done := range pending.waiters {
+		done <- batchResult{status: status, err: err}
+	}
+}
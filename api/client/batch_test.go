@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMountURL = "restconf/config/network-topology:network-topology/topology/topology-netconf/node/r1/yang-ext:mount"
+
+// testBatchWrites puts payloads to urls below mounts in order, each write is batched before the
+// next one starts so the edit-ids of the batches follow it, and returns the errors of the writes
+func testBatchWrites(t *testing.T, c *Client, urls ...string) []error {
+	results := make([]chan error, len(urls))
+	for i, url := range urls {
+		results[i] = make(chan error, 1)
+		go func(url string, result chan error) {
+			result <- c.PutNetconf(url, *bytes.NewBufferString(`{"vrf":[]}`))
+		}(url, results[i])
+		waitForBatchedEdits(t, c, i+1)
+	}
+
+	errs := []error{}
+	for _, result := range results {
+		errs = append(errs, <-result)
+	}
+	return errs
+}
+
+// waitForBatchedEdits waits until the batches of a client hold a number of edits
+func waitForBatchedEdits(t *testing.T, c *Client, edits int) {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		c.batcher.mutex.Lock()
+		batched := 0
+		for _, pending := range c.batcher.batches {
+			batched += len(pending.edits)
+		}
+		c.batcher.mutex.Unlock()
+		if batched >= edits {
+			return
+		}
+	}
+	t.Fatalf("expected %d edits to be batched", edits)
+}
+
+func TestBatchFlush(t *testing.T) {
+	server := newTestServer(http.StatusNoContent, "")
+	defer server.close()
+	c := server.client(WithBatchWindow(100 * time.Millisecond))
+
+	r2MountURL := strings.Replace(testMountURL, "/node/r1/", "/node/r2/", 1)
+	errs := testBatchWrites(t, c,
+		testMountURL+"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/blue",
+		testMountURL+"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/red",
+		r2MountURL+"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/blue")
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	patches := map[string]string{}
+	for _, request := range server.received() {
+		if request.method != "PATCH" {
+			t.Errorf("expected only patches, got %s %s", request.method, request.path)
+		}
+		patches[request.path] = request.body
+	}
+	if len(patches) != 2 {
+		t.Fatalf("expected a patch to each device, got %v", patches)
+	}
+	for _, expected := range []string{
+		`"comment":"2 edits batched by terraform"`,
+		`{"edit-id":"edit-1","operation":"replace","target":"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/blue","value":{"vrf":[]}}`,
+		`{"edit-id":"edit-2","operation":"replace","target":"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/red","value":{"vrf":[]}}`,
+	} {
+		if !strings.Contains(patches[testMountURL], expected) {
+			t.Errorf("expected the patch to r1 to contain %s, got %s", expected, patches[testMountURL])
+		}
+	}
+	if !strings.Contains(patches[r2MountURL], `"comment":"1 edits batched by terraform"`) {
+		t.Errorf("expected the write to r2 in its own patch, got %s", patches[r2MountURL])
+	}
+
+	// Writes after the flush go into a new batch
+	if err := c.DeleteNetconf(testMountURL + "/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/blue"); err != nil {
+		t.Fatal(err)
+	}
+	if requests := server.received(); len(requests) != 3 || !strings.Contains(requests[2].body, `"operation":"remove"`) {
+		t.Errorf("expected the delete to be sent in a patch of its own, got %v", requests)
+	}
+}
+
+func TestBatchEditErrors(t *testing.T) {
+	const editStatus = `{"ietf-yang-patch:yang-patch-status":{"patch-id":"terraform-1","edit-status":{"edit":[` +
+		`{"edit-id":"edit-1","ok":[null]},` +
+		`{"edit-id":"edit-2","errors":{"error":[{"error-type":"application","error-tag":"data-exists"}]}}]}}}`
+
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		// errs are the errors each write is expected to contain, empty when it isn't expected to fail
+		errs          []string
+		alreadyExists []bool
+	}{
+		{
+			name:          "failed edit",
+			status:        http.StatusConflict,
+			body:          editStatus,
+			errs:          []string{"another edit in the batch failed", "edit edit-1: data-exists"},
+			alreadyExists: []bool{false, true},
+		},
+		{
+			name:          "failed create without edit status",
+			status:        http.StatusConflict,
+			body:          `{"errors":{"error":[{"error-type":"protocol","error-tag":"data-exists"}]}}`,
+			errs:          []string{"another edit in the batch failed", "409 Conflict"},
+			alreadyExists: []bool{false, true},
+		},
+		{
+			name:          "failed batch",
+			status:        http.StatusInternalServerError,
+			body:          `{"errors":{"error":[{"error-type":"protocol","error-tag":"lock-denied"}]}}`,
+			errs:          []string{"lock-denied", "lock-denied"},
+			alreadyExists: []bool{false, false},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newTestServer(c.status, c.body)
+			defer server.close()
+			client := server.client(WithBatchWindow(100 * time.Millisecond))
+
+			results := make([]chan error, 2)
+			results[0] = make(chan error, 1)
+			go func() {
+				results[0] <- client.PutNetconf(testMountURL+"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/blue", *bytes.NewBufferString(`{"vrf":[]}`))
+			}()
+			waitForBatchedEdits(t, client, 1)
+			results[1] = make(chan error, 1)
+			go func() {
+				results[1] <- client.PostNetconf(testMountURL+"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/red", *bytes.NewBufferString(`{"vrf":[]}`))
+			}()
+
+			for i, result := range results {
+				err := <-result
+				if err == nil || !strings.Contains(err.Error(), c.errs[i]) {
+					t.Errorf("expected the error of write %d to contain %q, got %v", i+1, c.errs[i], err)
+				}
+				if errors.Is(err, ErrAlreadyExists) != c.alreadyExists[i] {
+					t.Errorf("expected the error of write %d to be ErrAlreadyExists %t, got %v", i+1, c.alreadyExists[i], err)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchCallerStatus(t *testing.T) {
+	server := newTestServer(http.StatusOK, `{"ietf-yang-patch:yang-patch-status":{"patch-id":"terraform-1","edit-status":{"edit":[`+
+		`{"edit-id":"edit-1","ok":[null]},{"edit-id":"edit-2","ok":[null]},{"edit-id":"edit-3","ok":[null]}]}}}`)
+	defer server.close()
+	c := server.client(WithBatchWindow(100 * time.Millisecond))
+
+	go c.PutNetconf(testMountURL+"/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/blue", *bytes.NewBufferString(`{"vrf":[]}`))
+	waitForBatchedEdits(t, c, 1)
+
+	patch := payload.NewYangPatch("update").
+		Merge("/Cisco-IOS-XR-infra-rsi-cfg:vrfs", *bytes.NewBufferString(`{"vrfs":{}}`)).
+		Append(payload.YangPatchEdit{EditID: "remove-red", Operation: "remove", Target: "/Cisco-IOS-XR-infra-rsi-cfg:vrfs/vrf/red"})
+	status, err := c.YangPatchNetconf(testMountURL, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editIDs := []string{}
+	for _, result := range status.EditStatus.Edit {
+		editIDs = append(editIDs, result.EditID)
+	}
+	if strings.Join(editIDs, ",") != "edit-1,remove-red" {
+		t.Errorf("expected the status of the edits of the patch by their own ids, got %v", editIDs)
+	}
+}
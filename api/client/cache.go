@@ -0,0 +1,399 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"sync"
+)
+
+// cachedList is a list of a mounted device whose entries are served from a single GET of
+// the container it is in, instead of one GET per entry
+type cachedList struct {
+	container string   // path of the fetched container below the mount
+	path      []string // containers from the fetched container down to the list
+	list      string
+	keys      []string
+}
+
+// cachedLists are the lists many resources read entries of on the same device
+var cachedLists = []cachedList{
+	{
+		container: "Cisco-IOS-XR-ifmgr-cfg:interface-configurations",
+		list:      "interface-configuration",
+		keys:      []string{"active", "interface-name"},
+	},
+	{
+		container: "Cisco-IOS-XR-l2vpn-cfg:l2vpn/database",
+		path:      []string{"flexible-xconnect-service-table", "vlan-aware-flexible-xconnect-services"},
+		list:      "vlan-aware-flexible-xconnect-service",
+		keys:      []string{"eviid"},
+	},
+}
+
+// cachedContainer is a fetched container, done is closed once the GET returned
+type cachedContainer struct {
+	done    chan struct{}
+	data    interface{}
+	version Version
+	err     error
+}
+
+// readCache holds the containers of cachedLists fetched during the run. Once a device is
+// written to its containers are dropped and its entries are read one by one again, so the
+// cache speeds up refreshing without serving reads after a write from stale data
+type readCache struct {
+	mutex      sync.Mutex
+	containers map[string]*cachedContainer
+	written    map[string]bool
+}
+
+// WithReadCache serves the reads of interface-configurations and the l2vpn database of a
+// device from one GET of the whole container until the device is written to. Entries read
+// from the cache get the Last-Modified of the container as their Version, its ETag only
+// identifies the whole container, so the entries of a container sent with an ETag and
+// without Last-Modified are read one by one when their Version is needed
+func WithReadCache(enabled bool) Option {
+	return func(c *Client) {
+		if enabled {
+			c.readCache = &readCache{
+				containers: map[string]*cachedContainer{},
+				written:    map[string]bool{},
+			}
+		}
+	}
+}
+
+// cachedRead returns the entry at a url from the cached container it is in with only the nodes
+// the fields select, and its version when versioned. The read is not cached when caching is
+// disabled, the url is not an entry of a cached list or the version of the entry is needed and
+// the container doesn't give one
+func (c *Client) cachedRead(entryURL string, fields []string, versioned bool) (bodyBytes []byte, version Version, cached bool, err error) {
+	// Containers are looked into as json, XML replies are only parsed by the type they are read into
+	if c.readCache == nil || c.encoding != payload.JSON {
+		return nil, Version{}, false, nil
+	}
+	// Fields the cache can't parse are left to the controller to report
+	selection, err := parseFields(fields)
+	if err != nil {
+		return nil, Version{}, false, nil
+	}
+	mountURL, target, ok := payload.SplitMountURL(entryURL)
+	if !ok {
+		return nil, Version{}, false, nil
+	}
+
+	for _, list := range cachedLists {
+		keys, ok := list.entryKeys(target)
+		if !ok {
+			continue
+		}
+
+		data, containerVersion, err := c.cachedContainer(mountURL, list.container)
+		if err == errWritten {
+			return nil, Version{}, false, nil
+		}
+		if err != nil {
+			return nil, Version{}, true, err
+		}
+		// An entry isn't modified after its container, but its ETag is not the container's
+		version = Version{LastModified: containerVersion.LastModified}
+		if versioned && version.IsZero() && !containerVersion.IsZero() {
+			return nil, Version{}, false, nil
+		}
+
+		bodyBytes, err := list.entry(data, keys, selection)
+		if err != nil {
+			return nil, Version{}, true, fmt.Errorf("%s in cached %s: %w", entryURL, list.container, err)
+		}
+		log.Printf("[DEBUG] GET Body from cache: %s", redactSecrets(bodyBytes))
+		return bodyBytes, version, true, nil
+	}
+	return nil, Version{}, false, nil
+}
+
+// errWritten is returned for containers of devices that were written to during the run
+var errWritten = errors.New("device was written to")
+
+// cachedContainer returns a container of a device with its version, concurrent reads of a
+// container that isn't cached yet wait for a single GET
+func (c *Client) cachedContainer(mountURL string, container string) (interface{}, Version, error) {
+	cache := c.readCache
+	containerURL := mountURL + "/" + container
+
+	cache.mutex.Lock()
+	if cache.written[mountURL] {
+		cache.mutex.Unlock()
+		return nil, Version{}, errWritten
+	}
+	fetched, found := cache.containers[containerURL]
+	if !found {
+		fetched = &cachedContainer{done: make(chan struct{})}
+		cache.containers[containerURL] = fetched
+	}
+	cache.mutex.Unlock()
+
+	if !found {
+		bodyBytes, version, err := c.GetNetconfVersion(containerURL, RequestOptions{})
+		fetched.version = version
+		if err == nil {
+			// Numbers are kept as they were sent so numeric keys compare with the url
+			decoder := json.NewDecoder(bytes.NewReader(bodyBytes))
+			decoder.UseNumber()
+			err = decoder.Decode(&fetched.data)
+		}
+		fetched.err = err
+		close(fetched.done)
+
+		// Only a container that doesn't exist is cached as an error, others are fetched again
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			cache.mutex.Lock()
+			if cache.containers[containerURL] == fetched {
+				delete(cache.containers, containerURL)
+			}
+			cache.mutex.Unlock()
+		}
+	}
+
+	<-fetched.done
+	return fetched.data, fetched.version, fetched.err
+}
+
+// invalidateReadCache drops the cached containers of the device a write is sent to
+func (c *Client) invalidateReadCache(path string) {
+	if c.readCache == nil {
+		return
+	}
+	i := strings.Index(path, "/yang-ext:mount")
+	if i < 0 {
+		return
+	}
+	mountURL := path[:i+len("/yang-ext:mount")]
+
+	cache := c.readCache
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.written[mountURL] = true
+	for containerURL := range cache.containers {
+		if strings.HasPrefix(containerURL, mountURL+"/") {
+			delete(cache.containers, containerURL)
+		}
+	}
+}
+
+// entryKeys returns the unescaped keys of a target that is an entry of the list
+func (l cachedList) entryKeys(target string) ([]string, bool) {
+	prefix := "/" + strings.Join(append(append([]string{l.container}, l.path...), l.list), "/") + "/"
+	if !strings.HasPrefix(target, prefix) {
+		return nil, false
+	}
+
+	keys := strings.Split(strings.TrimPrefix(target, prefix), "/")
+	if len(keys) != len(l.keys) {
+		return nil, false
+	}
+	for i, key := range keys {
+		unescaped, err := url.PathUnescape(key)
+		if err != nil {
+			return nil, false
+		}
+		keys[i] = unescaped
+	}
+	return keys, true
+}
+
+// entry finds the entry with the keys in a fetched container and returns it as the body
+// a GET of the entry with the fields returns, ErrNotFound when it isn't in the container
+func (l cachedList) entry(data interface{}, keys []string, selection fieldSelection) ([]byte, error) {
+	node := data
+	for _, name := range append(append([]string{lastSegment(l.container)}, l.path...), l.list) {
+		node = child(node, name)
+	}
+
+	entries, _ := node.([]interface{})
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if l.matches(fields, keys) {
+			if selection != nil {
+				entry = selection.apply(fields)
+			}
+			return json.Marshal(map[string]interface{}{l.list: []interface{}{entry}})
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// matches reports whether the key leaves of an entry have the values of the keys
+func (l cachedList) matches(fields map[string]interface{}, keys []string) bool {
+	for i, name := range l.keys {
+		value := child(fields, name)
+		if value == nil || fmt.Sprint(value) != keys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// child returns a member of a json object by name, members qualified with a module name match too
+func child(node interface{}, name string) interface{} {
+	fields, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for member, value := range fields {
+		if member == name || strings.HasSuffix(member, ":"+name) {
+			return value
+		}
+	}
+	return nil
+}
+
+// fieldSelection are the nodes the fields query parameter selects by name, a nil selection
+// of a node selects the whole node
+type fieldSelection map[string]fieldSelection
+
+// parseFields parses the fields of a read as RFC 8040 defines the fields query parameter, ie
+// a;b/c;d(e;f), no fields select nothing and return a nil selection
+func parseFields(fields []string) (fieldSelection, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	expr := strings.Join(fields, ";")
+	selection, rest, err := parseFieldsExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected %q in fields %q", rest, expr)
+	}
+	return selection, nil
+}
+
+// parseFieldsExpr parses paths separated by ; up to a closing parenthesis or the end of the
+// expression, and returns what is left of the expression
+func parseFieldsExpr(expr string) (fieldSelection, string, error) {
+	selection := fieldSelection{}
+	for {
+		end := strings.IndexAny(expr, ";()")
+		if end < 0 {
+			end = len(expr)
+		}
+		path := expr[:end]
+		expr = expr[end:]
+		if path == "" {
+			return nil, expr, fmt.Errorf("missing node name in fields")
+		}
+
+		var sub fieldSelection
+		if strings.HasPrefix(expr, "(") {
+			var err error
+			sub, expr, err = parseFieldsExpr(expr[1:])
+			if err != nil {
+				return nil, expr, err
+			}
+			if !strings.HasPrefix(expr, ")") {
+				return nil, expr, fmt.Errorf("missing ) in fields")
+			}
+			expr = expr[1:]
+		}
+		if err := selection.add(strings.Split(path, "/"), sub); err != nil {
+			return nil, expr, err
+		}
+
+		if !strings.HasPrefix(expr, ";") {
+			return selection, expr, nil
+		}
+		expr = expr[1:]
+	}
+}
+
+// add selects the node at the end of a path with the selection of its children
+func (s fieldSelection) add(path []string, sub fieldSelection) error {
+	name := path[0]
+	if name == "" {
+		return fmt.Errorf("missing node name in fields")
+	}
+	existing, found := s[name]
+	// A node that is selected as a whole stays selected as a whole
+	if found && existing == nil {
+		return nil
+	}
+	if len(path) > 1 {
+		if existing == nil {
+			existing = fieldSelection{}
+			s[name] = existing
+		}
+		return existing.add(path[1:], sub)
+	}
+	if found && sub != nil {
+		for child, childSub := range sub {
+			if err := existing.add([]string{child}, childSub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	s[name] = sub
+	return nil
+}
+
+// apply returns the members of a json object the selection selects, the selection of a list
+// applies to each of its entries
+func (s fieldSelection) apply(node interface{}) interface{} {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		selected := map[string]interface{}{}
+		for member, value := range node {
+			for name, sub := range s {
+				if !sameNode(member, name) {
+					continue
+				}
+				if sub == nil {
+					selected[member] = value
+				} else {
+					selected[member] = sub.apply(value)
+				}
+			}
+		}
+		return selected
+	case []interface{}:
+		entries := make([]interface{}, len(node))
+		for i, entry := range node {
+			entries[i] = s.apply(entry)
+		}
+		return entries
+	default:
+		return node
+	}
+}
+
+// sameNode reports whether a json member is the node of a name, the module name only tells
+// nodes apart when both of them have one
+func sameNode(member string, name string) bool {
+	if member == name {
+		return true
+	}
+	if strings.Contains(member, ":") && strings.Contains(name, ":") {
+		return false
+	}
+	return lastSegment(member) == lastSegment(name)
+}
+
+// lastSegment returns the node name at the end of a path, without its module name
+func lastSegment(path string) string {
+	segments := strings.Split(path, "/")
+	last := segments[len(segments)-1]
+	if i := strings.Index(last, ":"); i >= 0 {
+		return last[i+1:]
+	}
+	return last
+}
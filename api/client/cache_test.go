@@ -0,0 +1,186 @@
+package client
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testInterfacesURL = "restconf/config/network-topology:network-topology/topology/topology-netconf/node/r1/yang-ext:mount/" +
+	"Cisco-IOS-XR-ifmgr-cfg:interface-configurations"
+
+const testInterfaces = `{"interface-configurations":{"interface-configuration":[` +
+	`{"active":"act","interface-name":"GigabitEthernet0/0/0/1","description":"uplink"}]}}`
+
+// gets returns the paths of the GETs the fake controller got
+func (s *testServer) gets() []string {
+	gets := []string{}
+	for _, r := range s.received() {
+		if r.method == "GET" {
+			gets = append(gets, r.path)
+		}
+	}
+	return gets
+}
+
+func TestCachedReadKeepsLastModified(t *testing.T) {
+	server := newTestServer(http.StatusNotFound, "")
+	defer server.close()
+	server.reply(testInterfacesURL, http.StatusOK, http.Header{
+		"Etag":          {`"container"`},
+		"Last-Modified": {"Mon, 19 Oct 2026 10:00:00 GMT"},
+	}, testInterfaces)
+	c := server.client(WithReadCache(true))
+
+	bodyBytes, version, err := c.GetNetconfVersion(testInterfaceURL, RequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bodyBytes), "uplink") {
+		t.Errorf("expected the entry from the container, got %s", bodyBytes)
+	}
+	// The ETag of the container isn't the entry's, so only its Last-Modified is used
+	expected := Version{LastModified: "Mon, 19 Oct 2026 10:00:00 GMT"}
+	if version != expected {
+		t.Errorf("expected version %+v, got %+v", expected, version)
+	}
+	if gets := server.gets(); len(gets) != 1 || gets[0] != testInterfacesURL {
+		t.Errorf("expected a single GET of the container, got %v", gets)
+	}
+}
+
+func TestCachedReadWithoutLastModifiedReadsEntry(t *testing.T) {
+	server := newTestServer(http.StatusNotFound, "")
+	defer server.close()
+	server.reply(testInterfacesURL, http.StatusOK, http.Header{"Etag": {`"container"`}}, testInterfaces)
+	server.reply(testInterfaceURL, http.StatusOK, http.Header{"Etag": {`"entry"`}},
+		`{"interface-configuration":[{"active":"act","interface-name":"GigabitEthernet0/0/0/1","description":"uplink"}]}`)
+	c := server.client(WithReadCache(true))
+
+	_, version, err := c.GetNetconfVersion(testInterfaceURL, RequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.ETag != `"entry"` {
+		t.Errorf("expected the ETag of the entry, got %+v", version)
+	}
+
+	// Reads that don't need the version are still served from the container
+	if _, err := c.GetNetconf(testInterfaceURL); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{testInterfacesURL, testInterfaceURL}
+	if gets := server.gets(); strings.Join(gets, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected GETs %v, got %v", expected, gets)
+	}
+}
+
+func TestCachedReadWithoutVersion(t *testing.T) {
+	server := newTestServer(http.StatusNotFound, "")
+	defer server.close()
+	server.reply(testInterfacesURL, http.StatusOK, nil, testInterfaces)
+	c := server.client(WithReadCache(true))
+
+	_, version, err := c.GetNetconfVersion(testInterfaceURL, RequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !version.IsZero() {
+		t.Errorf("expected no version from a controller that sends none, got %+v", version)
+	}
+	if gets := server.gets(); len(gets) != 1 {
+		t.Errorf("expected the entry to be served from the container, got %v", gets)
+	}
+}
+
+func TestCachedReadSelectsFields(t *testing.T) {
+	server := newTestServer(http.StatusNotFound, "")
+	defer server.close()
+	server.reply(testInterfacesURL, http.StatusOK, nil, `{"interface-configurations":{"interface-configuration":[`+
+		`{"active":"act","interface-name":"GigabitEthernet0/0/0/1","description":"uplink","shutdown":[null],`+
+		`"Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service":{"encapsulation":{"outer-tag-type":"match-dot1q"},"local-traffic-default-encapsulation":{}},`+
+		`"mtus":{"mtu":[{"owner":"GigabitEthernet","mtu":1514}]}}]}}`)
+	c := server.client(WithReadCache(true))
+
+	bodyBytes, err := c.GetNetconfWithOptions(testInterfaceURL, RequestOptions{
+		Fields: []string{"interface-name", "ethernet-service(encapsulation;rewrite)", "mtus/mtu/mtu"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"interface-configuration":[{"Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service":{"encapsulation":{"outer-tag-type":"match-dot1q"}},` +
+		`"interface-name":"GigabitEthernet0/0/0/1","mtus":{"mtu":[{"mtu":1514}]}}]}`
+	if string(bodyBytes) != expected {
+		t.Errorf("expected the selected fields\n%s\ngot\n%s", expected, bodyBytes)
+	}
+	if gets := server.gets(); len(gets) != 1 || gets[0] != testInterfacesURL {
+		t.Errorf("expected a single GET of the container, got %v", gets)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		expected fieldSelection
+		err      bool
+	}{
+		{
+			name:     "none",
+			expected: nil,
+		},
+		{
+			name:     "leaves",
+			fields:   []string{"a", "b"},
+			expected: fieldSelection{"a": nil, "b": nil},
+		},
+		{
+			name:     "paths",
+			fields:   []string{"a/b", "a/c", "d"},
+			expected: fieldSelection{"a": {"b": nil, "c": nil}, "d": nil},
+		},
+		{
+			name:     "sub selection",
+			fields:   []string{"m:a(b;c/d)"},
+			expected: fieldSelection{"m:a": {"b": nil, "c": {"d": nil}}},
+		},
+		{
+			name:     "whole node",
+			fields:   []string{"a/b", "a", "a(c)"},
+			expected: fieldSelection{"a": nil},
+		},
+		{
+			name:   "unclosed",
+			fields: []string{"a(b"},
+			err:    true,
+		},
+		{
+			name:   "empty name",
+			fields: []string{"a//b"},
+			err:    true,
+		},
+		{
+			name:   "trailing parenthesis",
+			fields: []string{"a)"},
+			err:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selection, err := parseFields(test.fields)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error, got %v", selection)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(selection, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, selection)
+			}
+		})
+	}
+}
@@ -8,49 +8,235 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 )
 
 // ErrNotFound is the error for a HTTP 404
 var ErrNotFound = errors.New("not found")
 
+// ErrAlreadyExists is the error for a HTTP 409 when the data to create is already configured
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrPreconditionFailed is the error for a HTTP 412 when a conditional write finds the data changed
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ErrNotConnected is the error for a mounted device that is not connected yet
+var ErrNotConnected = errors.New("not connected")
+
 // Client holds all of the information required to connect to a controller
 type Client struct {
 	hostname   string
 	port       int
 	authToken  string
 	httpClient *http.Client
+
+	yangModulesMutex sync.Mutex
+	yangModules      map[string]*deviceYangModules
+
+	requestSlots       chan struct{}
+	maxPerDevice       int
+	deviceSlotsMutex   sync.Mutex
+	deviceRequestSlots map[string]chan struct{}
+
+	batcher   *batcher
+	readCache *readCache
+	encoding  payload.Encoding
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithEncoding sends payloads and requests replies in an encoding, the default is payload.JSON.
+// YANG-Patches are always sent as json
+func WithEncoding(encoding payload.Encoding) Option {
+	return func(c *Client) {
+		c.encoding = encoding
+	}
+}
+
+// WithMaxConcurrentRequests limits the requests in flight to the controller, 0 is unlimited
+func WithMaxConcurrentRequests(max int) Option {
+	return func(c *Client) {
+		if max > 0 {
+			c.requestSlots = make(chan struct{}, max)
+		}
+	}
+}
+
+// WithMaxConcurrentPerDevice limits the writes in flight to each mounted device, 0 is unlimited
+func WithMaxConcurrentPerDevice(max int) Option {
+	return func(c *Client) {
+		c.maxPerDevice = max
+	}
 }
 
 // NewClient returns a new Lumina SDN controller client
-func NewClient(hostname string, port int, token string) *Client {
-	return &Client{
+func NewClient(hostname string, port int, token string, opts ...Option) *Client {
+	c := &Client{
 		hostname:   hostname,
 		port:       port,
 		authToken:  token,
 		httpClient: &http.Client{},
+
+		yangModules: map[string]*deviceYangModules{},
+
+		deviceRequestSlots: map[string]chan struct{}{},
+
+		encoding: payload.JSON,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// deviceYangModules holds the YANG modules of a device once they are fetched, its mutex is held
+// while they are so only the first caller fetches them without blocking the other devices
+type deviceYangModules struct {
+	mutex   sync.Mutex
+	modules map[string]payload.YangModule
+}
+
+// YangModules returns the YANG modules a mounted device advertises, they are fetched
+// once the device is connected and cached until ForgetYangModules is called
+func (c *Client) YangModules(device string) (map[string]payload.YangModule, error) {
+	c.yangModulesMutex.Lock()
+	cached, ok := c.yangModules[device]
+	if !ok {
+		cached = &deviceYangModules{}
+		c.yangModules[device] = cached
+	}
+	c.yangModulesMutex.Unlock()
+
+	cached.mutex.Lock()
+	defer cached.mutex.Unlock()
+
+	if cached.modules != nil {
+		return cached.modules, nil
+	}
+
+	bodyBytes, err := c.GetNetconf(payload.NetconfMountURLOperational(device))
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := payload.ParseNetconfOperationalMountPayload(bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if node.Status != "connected" {
+		return nil, fmt.Errorf("device %s is %s: %w", device, node.Status, ErrNotConnected)
+	}
+
+	cached.modules = node.YangModules()
+	return cached.modules, nil
+}
+
+// ForgetYangModules drops the cached YANG modules of a device, ie when it is remounted.
+// A fetch in flight keeps its modules to itself, the next call fetches them again
+func (c *Client) ForgetYangModules(device string) {
+	c.yangModulesMutex.Lock()
+	defer c.yangModulesMutex.Unlock()
+
+	delete(c.yangModules, device)
 }
 
 // GetNetconf gets a generic netconf endpoint with a url from the controller
 func (c *Client) GetNetconf(url string) ([]byte, error) {
-	body, err := c.httpRequest(url, "GET", bytes.Buffer{})
+	return c.GetNetconfWithOptions(url, RequestOptions{})
+}
+
+// GetNetconfWithOptions gets a generic netconf endpoint with the query parameters of the options
+func (c *Client) GetNetconfWithOptions(url string, opts RequestOptions) ([]byte, error) {
+	bodyBytes, _, err := c.getNetconf(url, opts, false)
+	return bodyBytes, err
+}
+
+// Version identifies the revision of the data at a url by the ETag and Last-Modified headers
+// the controller returned for it, controllers that send neither give an empty Version
+type Version struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero reports whether the controller returned no version
+func (v Version) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// String returns the ETag of the version, or its Last-Modified when it has no ETag, as it is kept in state
+func (v Version) String() string {
+	if v.ETag != "" {
+		return v.ETag
+	}
+	return v.LastModified
+}
+
+// ParseVersion parses a version kept in state, ETags are quoted so they are told apart from dates
+func ParseVersion(version string) Version {
+	if strings.HasPrefix(version, `"`) || strings.HasPrefix(version, `W/"`) {
+		return Version{ETag: version}
+	}
+	return Version{LastModified: version}
+}
+
+// header returns the precondition headers that fail a write when the data no longer has this version
+func (v Version) header() http.Header {
+	header := http.Header{}
+	if v.ETag != "" {
+		header.Set("If-Match", v.ETag)
+	} else if v.LastModified != "" {
+		header.Set("If-Unmodified-Since", v.LastModified)
+	}
+	return header
+}
+
+// GetNetconfVersion gets a generic netconf endpoint like GetNetconfWithOptions and returns the version of the data with it
+func (c *Client) GetNetconfVersion(url string, opts RequestOptions) ([]byte, Version, error) {
+	return c.getNetconf(url, opts, true)
+}
+
+// getNetconf gets a generic netconf endpoint and its version, reads that need the version are
+// only served from the read cache when it has one
+func (c *Client) getNetconf(url string, opts RequestOptions, versioned bool) ([]byte, Version, error) {
+	if opts.servedFromCache() {
+		if bodyBytes, version, cached, err := c.cachedRead(url, opts.Fields, versioned); cached {
+			return bodyBytes, version, err
+		}
+	}
+
+	query, err := opts.query()
 	if err != nil {
-		return nil, err
+		return nil, Version{}, err
 	}
 
-	bodyBytes, err := ioutil.ReadAll(body)
+	resp, err := c.do(url+query, "GET", bytes.Buffer{}, nil)
+	if err != nil {
+		return nil, Version{}, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil, err
+		return nil, Version{}, err
 	}
 
-	log.Printf("[DEBUG] GET Body: ", string(bodyBytes))
+	log.Printf("[DEBUG] GET Body: %s", redactSecrets(bodyBytes))
 
-	return bodyBytes, nil
+	return bodyBytes, Version{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}, nil
 }
 
 // DeleteNetconf deletes a generic netconf endpoint from the controller
 func (c *Client) DeleteNetconf(url string) error {
+	if batched, err := c.batchWrite(url, payload.NewYangPatch("").Remove("")); batched {
+		return err
+	}
+
 	_, err := c.httpRequest(url, "DELETE", bytes.Buffer{})
 	if err != nil {
 		return err
@@ -60,6 +246,10 @@ func (c *Client) DeleteNetconf(url string) error {
 
 // PutNetconf puts a netconf payload at a specific url mount point
 func (c *Client) PutNetconf(url string, payloadBody bytes.Buffer) error {
+	if batched, err := c.batchWrite(url, payload.NewYangPatch("").Replace("", payloadBody)); batched {
+		return err
+	}
+
 	_, err := c.httpRequest(url, "PUT", payloadBody)
 	if err != nil {
 		return err
@@ -67,45 +257,328 @@ func (c *Client) PutNetconf(url string, payloadBody bytes.Buffer) error {
 	return nil
 }
 
+// PutNetconfIfUnchanged puts a netconf payload like PutNetconf, but fails with ErrPreconditionFailed
+// when the data at the url no longer has the version. Conditional writes are never batched
+func (c *Client) PutNetconfIfUnchanged(url string, payloadBody bytes.Buffer, version Version) error {
+	if version.IsZero() {
+		return c.PutNetconf(url, payloadBody)
+	}
+
+	resp, err := c.do(url, "PUT", payloadBody, version.header())
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// DeleteNetconfIfUnchanged deletes like DeleteNetconf, but fails with ErrPreconditionFailed
+// when the data at the url no longer has the version. Conditional writes are never batched
+func (c *Client) DeleteNetconfIfUnchanged(url string, version Version) error {
+	if version.IsZero() {
+		return c.DeleteNetconf(url)
+	}
+
+	resp, err := c.do(url, "DELETE", bytes.Buffer{}, version.header())
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PostNetconf creates a netconf payload at a url and fails with ErrAlreadyExists when it is already
+// configured. Below a mount point it is sent to the mount as a YANG-Patch with a single create edit,
+// other urls are list entries with a single key and the payload is posted to the list's parent
+func (c *Client) PostNetconf(url string, payloadBody bytes.Buffer) error {
+	if mountURL, target, ok := payload.SplitMountURL(url); ok {
+		_, err := c.YangPatchNetconf(mountURL, payload.NewYangPatch("create").Create(target, payloadBody))
+		return err
+	}
+
+	_, err := c.httpRequest(parentURL(url), "POST", payloadBody)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// PatchNetconf merges a netconf payload into the configuration at a url. Draft02 has no plain
+// merge so it is sent as a YANG-Patch with a single merge edit, to the mount for urls below one
+// so it can be batched with the other writes to the device, and to the url itself otherwise
+func (c *Client) PatchNetconf(url string, payloadBody bytes.Buffer) error {
+	if mountURL, target, ok := payload.SplitMountURL(url); ok {
+		_, err := c.YangPatchNetconf(mountURL, payload.NewYangPatch("merge").Merge(target, payloadBody))
+		return err
+	}
+	if !strings.HasPrefix(url, "restconf/config/") {
+		return fmt.Errorf("can't merge into %s, only the config datastore can be written", url)
+	}
+	_, err := c.sendYangPatch(url, payload.NewYangPatch("merge").Merge("/", payloadBody), nil)
+	return err
+}
+
+// YangPatchNetconf sends a YANG-Patch to a url, the targets of its edits are relative to the url.
+// The status is returned with an error for each failed edit when the patch is rejected
+func (c *Client) YangPatchNetconf(url string, patch *payload.YangPatch) (payload.YangPatchStatus, error) {
+	if c.batcher != nil && strings.HasSuffix(url, "/yang-ext:mount") {
+		return c.batchPatch(url, patch)
+	}
+	return c.sendYangPatch(url, patch, nil)
+}
+
+// YangPatchNetconfIfUnchanged sends a YANG-Patch to a url like YangPatchNetconf, but fails with
+// ErrPreconditionFailed when the data at the url no longer has the version. Edits with an empty
+// target edit the data at the url. Conditional patches are never batched
+func (c *Client) YangPatchNetconfIfUnchanged(url string, patch *payload.YangPatch, version Version) (payload.YangPatchStatus, error) {
+	// The targets are changed for the url, the patch may be sent again when it fails
+	patch = &payload.YangPatch{
+		PatchID: patch.PatchID,
+		Comment: patch.Comment,
+		Edit:    append([]payload.YangPatchEdit{}, patch.Edit...),
+	}
+
+	if version.IsZero() {
+		if batched, err := c.batchWrite(url, patch); batched {
+			return payload.YangPatchStatus{}, err
+		}
+	}
+	for i := range patch.Edit {
+		if patch.Edit[i].Target == "" {
+			patch.Edit[i].Target = "/"
+		}
+	}
+	return c.sendYangPatch(url, patch, version.header())
+}
+
+// sendYangPatch sends a YANG-Patch with extra headers and returns an error when any of its edits
+// failed, a failed precondition of the headers is ErrPreconditionFailed
+func (c *Client) sendYangPatch(url string, patch *payload.YangPatch, header http.Header) (payload.YangPatchStatus, error) {
+	payloadBody, err := payload.NetconfYangPatchPayload(*patch)
+	if err != nil {
+		return payload.YangPatchStatus{}, err
+	}
+
+	resp, err := c.do(url, "PATCH", payloadBody, header)
+	if err != nil {
+		// A rejected patch has the status of each edit in the body of the error
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && len(statusErr.body) > 0 {
+			if status, parseErr := payload.ParseNetconfYangPatchStatus(statusErr.body); parseErr == nil {
+				return status, err
+			}
+		}
+		return payload.YangPatchStatus{}, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return payload.YangPatchStatus{}, err
+	}
+	if len(bytes.TrimSpace(bodyBytes)) == 0 {
+		return payload.YangPatchStatus{PatchID: patch.PatchID}, nil
+	}
+
+	status, err := payload.ParseNetconfYangPatchStatus(bodyBytes)
+	if err != nil {
+		return status, err
+	}
+	return status, status.Err()
+}
+
+// InvokeRPC posts an RPC input payload to an operations url and returns the RPC output
+func (c *Client) InvokeRPC(url string, payloadBody bytes.Buffer) ([]byte, error) {
+	body, err := c.httpRequest(url, "POST", payloadBody)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] RPC Output: %s", redactSecrets(bodyBytes))
+
+	return bodyBytes, nil
+}
+
 // httpRequest calls generic HTTP requests
 func (c *Client) httpRequest(path string, method string, body bytes.Buffer) (closer io.ReadCloser, err error) {
+	resp, err := c.do(path, method, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a HTTP request with extra headers and returns the response, non 200 status codes are errors
+func (c *Client) do(path string, method string, body bytes.Buffer, header http.Header) (*http.Response, error) {
+	if method == "PUT" || method == "POST" {
+		encoded, err := c.encoding.Encode(body, payload.PathModule(path))
+		if err != nil {
+			return nil, err
+		}
+		body = encoded
+	}
+
 	req, err := http.NewRequest(method, c.requestPath(path), &body)
 	if err != nil {
 		return nil, err
 	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
 	req.Header.Add("Authorization", c.authToken)
 	switch method {
 	case "GET":
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Accept", "application/json")
+		req.Header.Add("Content-Type", c.encoding.MediaType())
+		req.Header.Add("Accept", c.encoding.MediaType())
 	case "DELETE":
+	case "PATCH":
+		req.Header.Add("Content-Type", "application/yang.patch+json")
+		req.Header.Add("Accept", "application/yang.patch-status+json")
 	default:
-		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add("Content-Type", c.encoding.MediaType())
 	}
 
-	log.Printf("[DEBUG] API call: ", req)
+	if method != "GET" {
+		c.invalidateReadCache(path)
+	}
+
+	release := c.acquireRequestSlot(path, method)
+	// The request isn't logged whole, its body and Authorization header hold credentials
+	log.Printf("[DEBUG] API call: %s %s", method, req.URL)
 
 	resp, err := c.httpClient.Do(req)
+	release()
 
 	if err != nil {
-		log.Printf("[DEBUG] API Error: ", err)
+		log.Printf("[DEBUG] API Error: %v", err)
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
 		respBody := new(bytes.Buffer)
 		_, err := respBody.ReadFrom(resp.Body)
 		if err != nil {
 			return nil, fmt.Errorf("got a non 200 status code: %v", resp.StatusCode)
 		}
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("404 Notfound: %w", ErrNotFound)
-		}
-		return nil, fmt.Errorf("got a non 200 status code: %v - %s", resp.StatusCode, respBody.String())
+		return nil, newStatusError(resp.StatusCode, respBody.Bytes())
 	}
-	return resp.Body, nil
+	return resp, nil
+}
+
+// statusError is the error for a response with a non 200 status code, it wraps
+// ErrNotFound, ErrAlreadyExists and ErrPreconditionFailed for the status codes they stand for
+type statusError struct {
+	statusCode int
+	body       []byte
+	err        error
+}
+
+func newStatusError(statusCode int, body []byte) *statusError {
+	e := &statusError{statusCode: statusCode, body: body}
+	switch {
+	case statusCode == http.StatusNotFound:
+		e.err = ErrNotFound
+	// A 409 is also returned when the device's configuration is locked, only data-exists means it is configured
+	case statusCode == http.StatusConflict && bytes.Contains(body, []byte("data-exists")):
+		e.err = ErrAlreadyExists
+	case statusCode == http.StatusPreconditionFailed:
+		e.err = ErrPreconditionFailed
+	}
+	return e
+}
+
+func (e *statusError) Error() string {
+	switch e.err {
+	case ErrNotFound:
+		return fmt.Sprintf("404 Notfound: %v", e.err)
+	case ErrAlreadyExists:
+		return fmt.Sprintf("409 Conflict: %v - %s", e.err, e.body)
+	case ErrPreconditionFailed:
+		return fmt.Sprintf("412 Precondition Failed: %v", e.err)
+	}
+	return fmt.Sprintf("got a non 200 status code: %v - %s", e.statusCode, e.body)
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
 }
 
 func (c *Client) requestPath(path string) string {
 	return fmt.Sprintf("%s:%v/%s", c.hostname, c.port, path)
 }
+
+// secretJSON and secretXML match the values of json members and XML elements holding credentials,
+// ie netconf-node-topology:password, the passwords of call-home devices and private keys
+var (
+	secretJSON = regexp.MustCompile(`("[\w:-]*(?:password|secret|private-key)[\w-]*"\s*:\s*)("(?:[^"\\]|\\.)*"|\[[^\]]*\])`)
+	secretXML  = regexp.MustCompile(`(<((?:[\w-]+:)?[\w-]*(?:password|secret|private-key)[\w-]*)(?:\s[^>]*)?>)[^<]*(</)`)
+)
+
+// redactSecrets returns a reply with the values of its credentials replaced so it can be logged
+func redactSecrets(body []byte) string {
+	redacted := secretJSON.ReplaceAll(body, []byte(`${1}"<redacted>"`))
+	redacted = secretXML.ReplaceAll(redacted, []byte(`${1}redacted${3}`))
+	return string(redacted)
+}
+
+// parentURL returns the url of the list a single keyed list entry is in, ie the topology of a node
+func parentURL(path string) string {
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 {
+		return path
+	}
+	return strings.Join(segments[:len(segments)-2], "/")
+}
+
+// mountedDevice returns the node of a url at or below yang-ext:mount, ie the mount batches are
+// sent to, urls of the controller itself and of the node in the netconf topology are not for a device
+func mountedDevice(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 0; i+2 < len(segments); i++ {
+		if segments[i] == "node" && segments[i+2] == "yang-ext:mount" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// acquireRequestSlot waits for a free controller slot and, for writes to a mount, a free
+// slot of the device so concurrent commits don't fail on the device's configuration lock
+func (c *Client) acquireRequestSlot(path string, method string) (release func()) {
+	slots := []chan struct{}{}
+	if device := mountedDevice(path); device != "" && method != "GET" && c.maxPerDevice > 0 {
+		c.deviceSlotsMutex.Lock()
+		if _, ok := c.deviceRequestSlots[device]; !ok {
+			c.deviceRequestSlots[device] = make(chan struct{}, c.maxPerDevice)
+		}
+		slots = append(slots, c.deviceRequestSlots[device])
+		c.deviceSlotsMutex.Unlock()
+	}
+	if c.requestSlots != nil {
+		slots = append(slots, c.requestSlots)
+	}
+
+	// The device slot is taken before the controller slot, so requests queued
+	// behind a busy device don't hold controller slots other devices could use
+	start := time.Now()
+	for _, slot := range slots {
+		slot <- struct{}{}
+	}
+	if len(slots) > 0 {
+		log.Printf("[DEBUG] %s %s queued for %s", method, path, time.Since(start))
+	}
+
+	return func() {
+		for _, slot := range slots {
+			<-slot
+		}
+	}
+}
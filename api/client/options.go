@@ -0,0 +1,89 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Values of the content query parameter
+const (
+	ContentConfig    = "config"
+	ContentNonconfig = "nonconfig"
+	ContentAll       = "all"
+)
+
+// Values of the with-defaults query parameter
+const (
+	WithDefaultsReportAll       = "report-all"
+	WithDefaultsReportAllTagged = "report-all-tagged"
+	WithDefaultsTrim            = "trim"
+	WithDefaultsExplicit        = "explicit"
+)
+
+// RequestOptions are the RFC 8040 query parameters of a read, the zero value reads the whole subtree
+type RequestOptions struct {
+	// Depth limits the levels of the subtree that are returned, 0 is unbounded
+	Depth int
+	// Fields selects the nodes below the target that are returned, ie interface-name or mtus/mtu
+	// or Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service(encapsulation;rewrite)
+	Fields []string
+	// Content selects config, nonconfig or all data
+	Content string
+	// WithDefaults selects how leaves with default values are reported
+	WithDefaults string
+	// Filter is an XPath filter for the events of a stream
+	Filter string
+}
+
+// query returns the encoded query string of the options, starting with ? when there is one
+func (o RequestOptions) query() (string, error) {
+	params := []string{}
+	if o.Depth < 0 {
+		return "", fmt.Errorf("depth must be positive, got %d", o.Depth)
+	}
+	if o.Depth > 0 {
+		params = append(params, "depth="+strconv.Itoa(o.Depth))
+	}
+	if len(o.Fields) > 0 {
+		params = append(params, "fields="+escapeQueryValue(strings.Join(o.Fields, ";")))
+	}
+	switch o.Content {
+	case "":
+	case ContentConfig, ContentNonconfig, ContentAll:
+		params = append(params, "content="+o.Content)
+	default:
+		return "", fmt.Errorf("content must be config, nonconfig or all, got %q", o.Content)
+	}
+	switch o.WithDefaults {
+	case "":
+	case WithDefaultsReportAll, WithDefaultsReportAllTagged, WithDefaultsTrim, WithDefaultsExplicit:
+		params = append(params, "with-defaults="+o.WithDefaults)
+	default:
+		return "", fmt.Errorf("with-defaults must be report-all, report-all-tagged, trim or explicit, got %q", o.WithDefaults)
+	}
+	if o.Filter != "" {
+		params = append(params, "filter="+escapeQueryValue(o.Filter))
+	}
+
+	if len(params) == 0 {
+		return "", nil
+	}
+	return "?" + strings.Join(params, "&"), nil
+}
+
+// queryEscaper escapes what PathEscape keeps but ends a value or turns into a space in a query
+var queryEscaper = strings.NewReplacer("&", "%26", "+", "%2B")
+
+// escapeQueryValue percent-encodes a query value, spaces are %20 and not + so the controller
+// doesn't have to decode the value as a form
+func escapeQueryValue(value string) string {
+	return queryEscaper.Replace(url.PathEscape(value))
+}
+
+// servedFromCache reports whether the read cache can answer the read, it holds whole entries
+// so only the fields are applied to them
+func (o RequestOptions) servedFromCache() bool {
+	return o.Depth == 0 && o.Content == "" && o.WithDefaults == "" && o.Filter == ""
+}
@@ -0,0 +1,64 @@
+package client
+
+import "testing"
+
+func TestRequestOptionsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     RequestOptions
+		expected string
+		err      bool
+	}{
+		{
+			name:     "none",
+			expected: "",
+		},
+		{
+			name:     "depth and content",
+			opts:     RequestOptions{Depth: 2, Content: ContentNonconfig},
+			expected: "?depth=2&content=nonconfig",
+		},
+		{
+			name:     "fields",
+			opts:     RequestOptions{Fields: []string{"interface-name", "Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service(encapsulation;rewrite)", "mtus/mtu"}},
+			expected: "?fields=interface-name%3BCisco-IOS-XR-l2-eth-infra-cfg:ethernet-service%28encapsulation%3Brewrite%29%3Bmtus%2Fmtu",
+		},
+		{
+			name:     "filter with spaces",
+			opts:     RequestOptions{Filter: "/event[name = 'a b' and x+y & z]", WithDefaults: WithDefaultsTrim},
+			expected: "?with-defaults=trim&filter=%2Fevent%5Bname%20=%20%27a%20b%27%20and%20x%2By%20%26%20z%5D",
+		},
+		{
+			name: "negative depth",
+			opts: RequestOptions{Depth: -1},
+			err:  true,
+		},
+		{
+			name: "unknown content",
+			opts: RequestOptions{Content: "state"},
+			err:  true,
+		},
+		{
+			name: "unknown with-defaults",
+			opts: RequestOptions{WithDefaults: "all"},
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := test.opts.query()
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error, got %s", query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != test.expected {
+				t.Errorf("expected query\n%s\ngot\n%s", test.expected, query)
+			}
+		})
+	}
+}
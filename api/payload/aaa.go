@@ -0,0 +1,270 @@
+package payload
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// CiscoLocalUserPayload struct
+type CiscoLocalUserPayload struct {
+	Node []CiscoLocalUser `json:"username"`
+}
+
+// CiscoLocalUser struct represents a Cisco-IOS-XR-aaa-locald-cfg username
+type CiscoLocalUser struct {
+	Name                    string                   `json:"name"`
+	UsergroupUnderUsernames *UsergroupUnderUsernames `json:"usergroup-under-usernames,omitempty"`
+	Secret                  string                   `json:"secret,omitempty"`
+}
+
+// UsergroupUnderUsernames struct
+type UsergroupUnderUsernames struct {
+	UsergroupUnderUsername []UsergroupUnderUsername `json:"usergroup-under-username"`
+}
+
+// UsergroupUnderUsername struct
+type UsergroupUnderUsername struct {
+	Name string `json:"name"`
+}
+
+// CiscoAaaServerGroup struct represents a tacacs or radius server-group from
+// Cisco-IOS-XR-aaa-tacacs-cfg and Cisco-IOS-XR-aaa-protocol-radius-cfg
+type CiscoAaaServerGroup struct {
+	ServerGroupName string                 `json:"server-group-name"`
+	Servers         *AaaServerGroupServers `json:"servers,omitempty"`
+}
+
+// AaaServerGroupServers struct
+type AaaServerGroupServers struct {
+	Server []AaaServerGroupServer `json:"server"`
+}
+
+// AaaServerGroupServer struct, the port numbers only apply to radius server-groups
+type AaaServerGroupServer struct {
+	OrderingIndex  int    `json:"ordering-index"`
+	IPAddress      string `json:"ip-address"`
+	AuthPortNumber int    `json:"auth-port-number,omitempty"`
+	AcctPortNumber int    `json:"acct-port-number,omitempty"`
+}
+
+// aaaServerGroupModules maps the server-group protocol to the module augmenting aaa server-groups
+var aaaServerGroupModules = map[string]string{
+	"tacacs": "Cisco-IOS-XR-aaa-tacacs-cfg",
+	"radius": "Cisco-IOS-XR-aaa-protocol-radius-cfg",
+}
+
+const md5CryptMagic = "$1$"
+
+const md5CryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Md5Crypt hashes a password with the md5crypt ($1$) scheme IOS-XR uses for username secrets,
+// only the first 8 characters of the salt are used
+func Md5Crypt(password string, salt string) string {
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+	pw := []byte(password)
+
+	alt := md5.Sum([]byte(password + salt + password))
+
+	ctx := []byte(password + md5CryptMagic + salt)
+	for i := len(pw); i > 0; i -= 16 {
+		if i > 16 {
+			ctx = append(ctx, alt[:]...)
+		} else {
+			ctx = append(ctx, alt[:i]...)
+		}
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			ctx = append(ctx, 0)
+		} else {
+			ctx = append(ctx, pw[0])
+		}
+	}
+	final := md5.Sum(ctx)
+
+	for i := 0; i < 1000; i++ {
+		round := []byte{}
+		if i&1 == 1 {
+			round = append(round, pw...)
+		} else {
+			round = append(round, final[:]...)
+		}
+		if i%3 != 0 {
+			round = append(round, salt...)
+		}
+		if i%7 != 0 {
+			round = append(round, pw...)
+		}
+		if i&1 == 1 {
+			round = append(round, final[:]...)
+		} else {
+			round = append(round, pw...)
+		}
+		final = md5.Sum(round)
+	}
+
+	var hash strings.Builder
+	encode := func(v uint32, n int) {
+		for ; n > 0; n-- {
+			hash.WriteByte(md5CryptAlphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	encode(uint32(final[0])<<16|uint32(final[6])<<8|uint32(final[12]), 4)
+	encode(uint32(final[1])<<16|uint32(final[7])<<8|uint32(final[13]), 4)
+	encode(uint32(final[2])<<16|uint32(final[8])<<8|uint32(final[14]), 4)
+	encode(uint32(final[3])<<16|uint32(final[9])<<8|uint32(final[15]), 4)
+	encode(uint32(final[4])<<16|uint32(final[10])<<8|uint32(final[5]), 4)
+	encode(uint32(final[11]), 2)
+
+	return md5CryptMagic + salt + "$" + hash.String()
+}
+
+// NewMd5CryptSecret hashes a plaintext password with a random salt
+func NewMd5CryptSecret(password string) (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	salt := make([]byte, len(random))
+	for i, b := range random {
+		salt[i] = md5CryptAlphabet[b&0x3f]
+	}
+	return Md5Crypt(password, string(salt)), nil
+}
+
+// Md5CryptMatches reports whether a plaintext password hashes to an md5crypt secret
+func Md5CryptMatches(password string, secret string) bool {
+	if !strings.HasPrefix(secret, md5CryptMagic) {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(secret, md5CryptMagic), "$")
+	if len(parts) != 2 {
+		return false
+	}
+	return Md5Crypt(password, parts[0]) == secret
+}
+
+// NewCiscoLocalUser returns a username in the given usergroups
+func NewCiscoLocalUser(name string, groups []string, secret string) CiscoLocalUser {
+	user := CiscoLocalUser{
+		Name:   name,
+		Secret: secret,
+	}
+	if len(groups) > 0 {
+		user.UsergroupUnderUsernames = &UsergroupUnderUsernames{}
+		for _, group := range groups {
+			user.UsergroupUnderUsernames.UsergroupUnderUsername = append(user.UsergroupUnderUsernames.UsergroupUnderUsername, UsergroupUnderUsername{Name: group})
+		}
+	}
+	return user
+}
+
+// Groups returns the usergroups of the username
+func (u CiscoLocalUser) Groups() []string {
+	groups := []string{}
+	if u.UsergroupUnderUsernames != nil {
+		for _, group := range u.UsergroupUnderUsernames.UsergroupUnderUsername {
+			groups = append(groups, group.Name)
+		}
+	}
+	return groups
+}
+
+// NewCiscoAaaServerGroup returns a server-group with the servers in the given order
+func NewCiscoAaaServerGroup(name string, servers []AaaServerGroupServer) CiscoAaaServerGroup {
+	group := CiscoAaaServerGroup{
+		ServerGroupName: name,
+	}
+	if len(servers) > 0 {
+		group.Servers = &AaaServerGroupServers{}
+		for i, server := range servers {
+			server.OrderingIndex = i
+			group.Servers.Server = append(group.Servers.Server, server)
+		}
+	}
+	return group
+}
+
+// NetconfCiscoLocalUserURL returns netconf cisco local username URL
+func NetconfCiscoLocalUserURL(device string, name string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-aaa-lib-cfg:aaa/Cisco-IOS-XR-aaa-locald-cfg:usernames").
+		Entry("username", name).String()
+}
+
+// NetconfCiscoAaaServerGroupURL returns netconf cisco tacacs or radius server-group URL
+func NetconfCiscoAaaServerGroupURL(device string, protocol string, name string) string {
+	return ConfigPath().Mount(device).
+		Child(fmt.Sprintf("Cisco-IOS-XR-aaa-lib-cfg:aaa/server-groups/%s:%s-server-groups", aaaServerGroupModules[protocol], protocol)).
+		Entry(protocol+"-server-group", name).String()
+}
+
+// NetconfCiscoLocalUserPayload forms a json payload for cisco local username
+func NetconfCiscoLocalUserPayload(user CiscoLocalUser) (bytes.Buffer, error) {
+	payloadBody := CiscoLocalUserPayload{
+		Node: []CiscoLocalUser{user},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoLocalUserPayload parses json payload for cisco local username to a struct
+func ParseNetconfCiscoLocalUserPayload(bodyBytes []byte) (CiscoLocalUser, error) {
+	item := &CiscoLocalUserPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoLocalUser{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var user CiscoLocalUser = item.Node[0]
+	return user, nil
+}
+
+// NetconfCiscoAaaServerGroupPayload forms a json payload for cisco tacacs or radius server-group
+func NetconfCiscoAaaServerGroupPayload(protocol string, group CiscoAaaServerGroup) (bytes.Buffer, error) {
+	payloadBody := map[string][]CiscoAaaServerGroup{
+		protocol + "-server-group": {group},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoAaaServerGroupPayload parses json payload for cisco tacacs or radius server-group to a struct
+func ParseNetconfCiscoAaaServerGroupPayload(bodyBytes []byte) (CiscoAaaServerGroup, error) {
+	item := map[string][]CiscoAaaServerGroup{}
+	err := Unmarshal(bodyBytes, &item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoAaaServerGroup{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	for _, groups := range item {
+		if len(groups) > 0 {
+			return groups[0], nil
+		}
+	}
+	return CiscoAaaServerGroup{}, fmt.Errorf("no server-group in aaa payload: %w", ErrSchemaMismatch)
+}
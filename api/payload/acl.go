@@ -0,0 +1,346 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// CiscoACLPayload struct
+type CiscoACLPayload struct {
+	Node []CiscoACL `json:"access"`
+}
+
+// CiscoACL struct represents a Cisco-IOS-XR-ipv4-acl-cfg or Cisco-IOS-XR-ipv6-acl-cfg access list
+type CiscoACL struct {
+	AccessListName    string            `json:"access-list-name"`
+	AccessListEntries AccessListEntries `json:"access-list-entries"`
+}
+
+// AccessListEntries struct
+type AccessListEntries struct {
+	AccessListEntry []AccessListEntry `json:"access-list-entry"`
+}
+
+// AccessListEntry struct is a single ACE, ordered by sequence number
+type AccessListEntry struct {
+	SequenceNumber     int                    `json:"sequence-number"`
+	Grant              string                 `json:"grant"`
+	Protocol           string                 `json:"protocol,omitempty"`
+	SourceNetwork      *ACLSourceNetwork      `json:"source-network,omitempty"`
+	DestinationNetwork *ACLDestinationNetwork `json:"destination-network,omitempty"`
+	SourcePort         *ACLSourcePort         `json:"source-port,omitempty"`
+	DestinationPort    *ACLDestinationPort    `json:"destination-port,omitempty"`
+	Dscp               *ACLDscp               `json:"dscp,omitempty"`
+	LogOption          string                 `json:"log-option,omitempty"`
+}
+
+// ACLSourceNetwork struct, IPv4 uses wildcard bits and IPv6 uses a prefix length
+type ACLSourceNetwork struct {
+	SourceAddress      string `json:"source-address"`
+	SourceWildCardBits string `json:"source-wild-card-bits,omitempty"`
+	SourcePrefixLength *int   `json:"source-prefix-length,omitempty"`
+}
+
+// ACLDestinationNetwork struct, IPv4 uses wildcard bits and IPv6 uses a prefix length
+type ACLDestinationNetwork struct {
+	DestinationAddress      string `json:"destination-address"`
+	DestinationWildCardBits string `json:"destination-wild-card-bits,omitempty"`
+	DestinationPrefixLength *int   `json:"destination-prefix-length,omitempty"`
+}
+
+// ACLSourcePort struct
+type ACLSourcePort struct {
+	SourceOperator   string `json:"source-operator"`
+	FirstSourcePort  int    `json:"first-source-port"`
+	SecondSourcePort int    `json:"second-source-port,omitempty"`
+}
+
+// ACLDestinationPort struct
+type ACLDestinationPort struct {
+	DestinationOperator   string `json:"destination-operator"`
+	FirstDestinationPort  int    `json:"first-destination-port"`
+	SecondDestinationPort int    `json:"second-destination-port,omitempty"`
+}
+
+// ACLDscp struct
+type ACLDscp struct {
+	DscpOperator string `json:"dscp-operator"`
+	DscpMin      string `json:"dscp-min"`
+}
+
+// CiscoACLAttachment struct represents a Cisco-IOS-XR-ip-pfilter-cfg packet filter direction
+type CiscoACLAttachment struct {
+	Name string `json:"name"`
+}
+
+// NetconfCiscoACLURL returns netconf cisco access list URL for the ipv4 or ipv6 address family
+func NetconfCiscoACLURL(device string, addressFamily string, name string) string {
+	return ConfigPath().Mount(device).
+		Child(fmt.Sprintf("Cisco-IOS-XR-%s-acl-cfg:%s-acl-and-prefix-list/accesses", addressFamily, addressFamily)).
+		Entry("access", name).String()
+}
+
+// NetconfCiscoACLAttachmentURL returns netconf cisco packet filter URL, direction is inbound or outbound
+func NetconfCiscoACLAttachmentURL(device string, interfaceName string, addressFamily string, direction string) string {
+	return interfaceConfigurationPath(device, interfaceName).
+		Child(fmt.Sprintf("Cisco-IOS-XR-ip-pfilter-cfg:%s-packet-filter/%s", addressFamily, direction)).String()
+}
+
+// NetconfCiscoACLPayload forms a json payload for cisco access list
+func NetconfCiscoACLPayload(acl CiscoACL) (bytes.Buffer, error) {
+	payloadBody := CiscoACLPayload{
+		Node: []CiscoACL{acl},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoACLPayload parses json payload for cisco access list to a struct
+func ParseNetconfCiscoACLPayload(bodyBytes []byte) (CiscoACL, error) {
+	item := &CiscoACLPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoACL{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var acl CiscoACL = item.Node[0]
+	return acl, nil
+}
+
+// NetconfCiscoACLAttachmentPayload forms a json payload for a cisco packet filter direction
+func NetconfCiscoACLAttachmentPayload(direction string, attachment CiscoACLAttachment) (bytes.Buffer, error) {
+	payloadBody := map[string]CiscoACLAttachment{
+		direction: attachment,
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoACLAttachmentPayload parses json payload for a cisco packet filter direction to a struct
+func ParseNetconfCiscoACLAttachmentPayload(direction string, bodyBytes []byte) (CiscoACLAttachment, error) {
+	item := map[string]CiscoACLAttachment{}
+	err := Unmarshal(bodyBytes, &item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoACLAttachment{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	return item[direction], nil
+}
+
+// parseACLPrefix converts a CIDR prefix into the address and wildcard bits or prefix length of an ACE,
+// an address without a prefix length is a host. Prefixes with bits set past their length are rejected
+// as the device would read them back without those bits
+func parseACLPrefix(prefix string) (string, string, *int, error) {
+	if !strings.Contains(prefix, "/") {
+		if net.ParseIP(prefix).To4() != nil {
+			prefix += "/32"
+		} else {
+			prefix += "/128"
+		}
+	}
+	ip, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if !ip.Equal(network.IP) {
+		return "", "", nil, fmt.Errorf("%s has bits set past its prefix length, use %s", prefix, network)
+	}
+
+	if ip := network.IP.To4(); ip != nil {
+		wildcard := make(net.IP, 4)
+		for i := range wildcard {
+			wildcard[i] = ^network.Mask[i]
+		}
+		return ip.String(), wildcard.String(), nil, nil
+	}
+
+	length, _ := network.Mask.Size()
+	return network.IP.String(), "", &length, nil
+}
+
+// formatACLPrefix converts the address and wildcard bits or prefix length of an ACE into a CIDR prefix,
+// an IPv6 address without a prefix length is a host and a network of length 0 is "any"
+func formatACLPrefix(address string, wildcard string, length *int) string {
+	if address == "" {
+		return "any"
+	}
+	prefixLength := 128
+	if wildcard != "" {
+		bits := net.ParseIP(wildcard).To4()
+		if bits == nil {
+			return address
+		}
+		mask := make(net.IPMask, 4)
+		for i := range mask {
+			mask[i] = ^bits[i]
+		}
+		prefixLength, _ = mask.Size()
+	} else if length != nil {
+		prefixLength = *length
+	}
+	if prefixLength == 0 {
+		return "any"
+	}
+	return fmt.Sprintf("%s/%d", address, prefixLength)
+}
+
+// ValidateACLPrefix checks that a prefix is "any", an address or a CIDR prefix without bits set past its length
+func ValidateACLPrefix(prefix string) error {
+	if prefix == "" || prefix == "any" {
+		return nil
+	}
+	_, _, _, err := parseACLPrefix(prefix)
+	return err
+}
+
+// CanonicalACLPrefix returns a prefix as it is read back from the device, ie 10.1.1.5 is 10.1.1.5/32
+// and ::/0 is any. Prefixes that don't parse are returned unchanged
+func CanonicalACLPrefix(prefix string) string {
+	if prefix == "" || prefix == "any" {
+		return "any"
+	}
+	address, wildcard, length, err := parseACLPrefix(prefix)
+	if err != nil {
+		return prefix
+	}
+	return formatACLPrefix(address, wildcard, length)
+}
+
+// NewACLSourceNetwork returns the source network of an ACE, "any" and networks of length 0 return nil
+// as the ACE matches every address when the network is omitted
+func NewACLSourceNetwork(prefix string) (*ACLSourceNetwork, error) {
+	if CanonicalACLPrefix(prefix) == "any" {
+		return nil, nil
+	}
+	address, wildcard, length, err := parseACLPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &ACLSourceNetwork{SourceAddress: address, SourceWildCardBits: wildcard, SourcePrefixLength: length}, nil
+}
+
+// Prefix returns the source network in CIDR notation, or "any" when it is not set
+func (n *ACLSourceNetwork) Prefix() string {
+	if n == nil {
+		return "any"
+	}
+	return formatACLPrefix(n.SourceAddress, n.SourceWildCardBits, n.SourcePrefixLength)
+}
+
+// NewACLDestinationNetwork returns the destination network of an ACE, "any" and networks of length 0
+// return nil as the ACE matches every address when the network is omitted
+func NewACLDestinationNetwork(prefix string) (*ACLDestinationNetwork, error) {
+	if CanonicalACLPrefix(prefix) == "any" {
+		return nil, nil
+	}
+	address, wildcard, length, err := parseACLPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &ACLDestinationNetwork{DestinationAddress: address, DestinationWildCardBits: wildcard, DestinationPrefixLength: length}, nil
+}
+
+// Prefix returns the destination network in CIDR notation, or "any" when it is not set
+func (n *ACLDestinationNetwork) Prefix() string {
+	if n == nil {
+		return "any"
+	}
+	return formatACLPrefix(n.DestinationAddress, n.DestinationWildCardBits, n.DestinationPrefixLength)
+}
+
+// aclPortOperators are the operators of port matches other than equal and range by the keyword
+// they are written with, ie lt 1024
+var aclPortOperators = map[string]string{
+	"lt":  "less-than",
+	"gt":  "greater-than",
+	"neq": "not-equal",
+}
+
+// ParseACLPort parses a port match, a single port "80", a range "1024-65535" or a port with an
+// operator "lt 1024", "gt 1023" or "neq 22". Ports are 0 to 65535 and ranges go upwards
+func ParseACLPort(port string) (string, int, int, error) {
+	if port == "" {
+		return "", 0, 0, nil
+	}
+	if fields := strings.Fields(port); len(fields) == 2 {
+		operator, ok := aclPortOperators[fields[0]]
+		if !ok {
+			return "", 0, 0, fmt.Errorf("%q is not a port operator, use lt, gt or neq", fields[0])
+		}
+		first, err := parseACLPortNumber(fields[1])
+		if err != nil {
+			return "", 0, 0, err
+		}
+		return operator, first, 0, nil
+	}
+
+	if i := strings.Index(port, "-"); i > 0 {
+		first, err := parseACLPortNumber(port[:i])
+		if err != nil {
+			return "", 0, 0, err
+		}
+		second, err := parseACLPortNumber(port[i+1:])
+		if err != nil {
+			return "", 0, 0, err
+		}
+		if first > second {
+			return "", 0, 0, fmt.Errorf("port range %q starts after it ends", port)
+		}
+		return "range", first, second, nil
+	}
+	first, err := parseACLPortNumber(port)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return "equal", first, 0, nil
+}
+
+// parseACLPortNumber parses a port number of a port match
+func parseACLPortNumber(port string) (int, error) {
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a port, port range or port with an operator", port)
+	}
+	if number < 0 || number > 65535 {
+		return 0, fmt.Errorf("port %d is not between 0 and 65535", number)
+	}
+	return number, nil
+}
+
+// FormatACLPort formats a port match as parsed by ParseACLPort, matches with operators it
+// doesn't parse fail as they couldn't be configured again
+func FormatACLPort(operator string, first int, second int) (string, error) {
+	switch operator {
+	case "equal":
+		return strconv.Itoa(first), nil
+	case "range":
+		return fmt.Sprintf("%d-%d", first, second), nil
+	}
+	for keyword, name := range aclPortOperators {
+		if name == operator {
+			return fmt.Sprintf("%s %d", keyword, first), nil
+		}
+	}
+	return "", fmt.Errorf("port operator %q is not supported", operator)
+}
@@ -0,0 +1,147 @@
+package payload
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestACLPrefixRoundTrip(t *testing.T) {
+	tests := []struct {
+		prefix    string
+		canonical string
+	}{
+		{"any", "any"},
+		{"", "any"},
+		{"10.1.1.0/24", "10.1.1.0/24"},
+		{"10.1.1.5", "10.1.1.5/32"},
+		{"10.1.1.5/32", "10.1.1.5/32"},
+		{"0.0.0.0/0", "any"},
+		{"2001:db8::/32", "2001:db8::/32"},
+		{"2001:DB8:0::/32", "2001:db8::/32"},
+		{"2001:db8::1", "2001:db8::1/128"},
+		{"::/0", "any"},
+	}
+	for _, test := range tests {
+		t.Run(test.prefix, func(t *testing.T) {
+			if err := ValidateACLPrefix(test.prefix); err != nil {
+				t.Fatal(err)
+			}
+			if canonical := CanonicalACLPrefix(test.prefix); canonical != test.canonical {
+				t.Errorf("expected canonical prefix %s, got %s", test.canonical, canonical)
+			}
+
+			// The networks are read back from what is sent to the device
+			source, err := NewACLSourceNetwork(test.prefix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			destination, err := NewACLDestinationNetwork(test.prefix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry := AccessListEntry{SourceNetwork: source, DestinationNetwork: destination}
+			body, err := json.Marshal(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			read := AccessListEntry{}
+			if err := json.Unmarshal(body, &read); err != nil {
+				t.Fatal(err)
+			}
+			if prefix := read.SourceNetwork.Prefix(); prefix != test.canonical {
+				t.Errorf("expected source prefix %s to be read back, got %s from %s", test.canonical, prefix, body)
+			}
+			if prefix := read.DestinationNetwork.Prefix(); prefix != test.canonical {
+				t.Errorf("expected destination prefix %s to be read back, got %s from %s", test.canonical, prefix, body)
+			}
+		})
+	}
+}
+
+func TestACLPrefixInvalid(t *testing.T) {
+	for _, prefix := range []string{"10.1.1.5/24", "2001:db8::1/64", "10.1.1.0/33", "host"} {
+		t.Run(prefix, func(t *testing.T) {
+			if err := ValidateACLPrefix(prefix); err == nil {
+				t.Errorf("expected %s to be rejected", prefix)
+			}
+			if _, err := NewACLSourceNetwork(prefix); err == nil {
+				t.Errorf("expected no source network for %s", prefix)
+			}
+		})
+	}
+}
+
+func TestACLPrefixRead(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name     string
+		network  *ACLSourceNetwork
+		expected string
+	}{
+		{"no network", nil, "any"},
+		{"ipv4 wildcard", &ACLSourceNetwork{SourceAddress: "10.1.1.0", SourceWildCardBits: "0.0.0.255"}, "10.1.1.0/24"},
+		{"ipv4 all", &ACLSourceNetwork{SourceAddress: "0.0.0.0", SourceWildCardBits: "255.255.255.255"}, "any"},
+		{"ipv6 without length", &ACLSourceNetwork{SourceAddress: "2001:db8::1"}, "2001:db8::1/128"},
+		{"ipv6 of length 0", &ACLSourceNetwork{SourceAddress: "::", SourcePrefixLength: &zero}, "any"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if prefix := test.network.Prefix(); prefix != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, prefix)
+			}
+		})
+	}
+}
+
+func TestACLPort(t *testing.T) {
+	tests := []struct {
+		port     string
+		operator string
+		first    int
+		second   int
+	}{
+		{"", "", 0, 0},
+		{"80", "equal", 80, 0},
+		{"0", "equal", 0, 0},
+		{"1024-65535", "range", 1024, 65535},
+		{"80-80", "range", 80, 80},
+		{"lt 1024", "less-than", 1024, 0},
+		{"gt 1023", "greater-than", 1023, 0},
+		{"neq 22", "not-equal", 22, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.port, func(t *testing.T) {
+			operator, first, second, err := ParseACLPort(test.port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if operator != test.operator || first != test.first || second != test.second {
+				t.Errorf("expected %s %d %d, got %s %d %d", test.operator, test.first, test.second, operator, first, second)
+			}
+			if operator == "" {
+				return
+			}
+			formatted, err := FormatACLPort(operator, first, second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formatted != test.port {
+				t.Errorf("expected %s to be formatted back, got %s", test.port, formatted)
+			}
+		})
+	}
+}
+
+func TestACLPortInvalid(t *testing.T) {
+	for _, port := range []string{"http", "-1", "65536", "70000", "2000-1000", "1024-65536", "1024-", "le 1024", "lt", "lt 70000", "lt 10-20"} {
+		t.Run(port, func(t *testing.T) {
+			if _, _, _, err := ParseACLPort(port); err == nil {
+				t.Errorf("expected %q to be rejected", port)
+			}
+		})
+	}
+
+	if formatted, err := FormatACLPort("onebyte", 1, 0); err == nil {
+		t.Errorf("expected an unsupported operator to fail, got %q", formatted)
+	}
+}
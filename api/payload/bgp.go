@@ -0,0 +1,167 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+)
+
+// CiscoBgpNeighborPayload struct
+type CiscoBgpNeighborPayload struct {
+	Node []CiscoBgpNeighbor `json:"neighbor"`
+}
+
+// CiscoBgpNeighbor struct represents a Cisco-IOS-XR-ipv4-bgp-cfg default vrf neighbor
+type CiscoBgpNeighbor struct {
+	NeighborAddress       string          `json:"neighbor-address"`
+	RemoteAs              *BgpAs          `json:"remote-as,omitempty"`
+	UpdateSourceInterface string          `json:"update-source-interface,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	Password              *BgpPassword    `json:"password,omitempty"`
+	BfdEnableModes        string          `json:"bfd-enable-modes,omitempty"`
+	BfdMinimumInterval    int             `json:"bfd-minimum-interval,omitempty"`
+	BfdMultiplier         int             `json:"bfd-multiplier,omitempty"`
+	Timers                *BgpTimers      `json:"timers,omitempty"`
+	NeighborAfs           *BgpNeighborAfs `json:"neighbor-afs,omitempty"`
+}
+
+// BgpAs struct holds an AS split into its high and low order halves, as-xx is 0 for 2 byte ASes
+type BgpAs struct {
+	AsXx int `json:"as-xx"`
+	AsYy int `json:"as-yy"`
+}
+
+// BgpPassword struct
+type BgpPassword struct {
+	PasswordDisable bool   `json:"password-disable"`
+	Password        string `json:"password,omitempty"`
+}
+
+// BgpTimers struct
+type BgpTimers struct {
+	KeepaliveInterval int `json:"keepalive-interval"`
+	HoldTime          int `json:"hold-time"`
+}
+
+// BgpNeighborAfs struct
+type BgpNeighborAfs struct {
+	NeighborAf []BgpNeighborAf `json:"neighbor-af"`
+}
+
+// BgpNeighborAf struct is the per neighbor address family configuration
+type BgpNeighborAf struct {
+	AfName            string `json:"af-name"`
+	Activate          *Empty `json:"activate,omitempty"`
+	RoutePolicyIn     string `json:"route-policy-in,omitempty"`
+	RoutePolicyOut    string `json:"route-policy-out,omitempty"`
+	SendCommunityEbgp bool   `json:"send-community-ebgp,omitempty"`
+	NextHopSelf       bool   `json:"next-hop-self,omitempty"`
+}
+
+// CiscoBgpNeighborOperationalPayload struct
+type CiscoBgpNeighborOperationalPayload struct {
+	Node []CiscoBgpNeighborOperational `json:"neighbor"`
+}
+
+// CiscoBgpNeighborOperational struct represents a Cisco-IOS-XR-ipv4-bgp-oper neighbor session
+type CiscoBgpNeighborOperational struct {
+	NeighborAddress           string              `json:"neighbor-address"`
+	RemoteAs                  int                 `json:"remote-as"`
+	Description               string              `json:"description"`
+	ConnectionState           string              `json:"connection-state"`
+	ConnectionEstablishedTime int                 `json:"connection-established-time"`
+	MessagesReceived          int                 `json:"messages-received"`
+	MessagesSent              int                 `json:"messages-sent"`
+	AfData                    []BgpNeighborAfData `json:"af-data"`
+}
+
+// BgpNeighborAfData struct is the per address family session counters
+type BgpNeighborAfData struct {
+	AfName             string `json:"af-name"`
+	PrefixesAccepted   int    `json:"prefixes-accepted"`
+	PrefixesAdvertised int    `json:"prefixes-advertised"`
+}
+
+// NewBgpAs returns a BgpAs for a 2 or 4 byte AS, ASes above 65535 are split in asdot form
+func NewBgpAs(as int) *BgpAs {
+	return &BgpAs{AsXx: as >> 16, AsYy: as & 0xffff}
+}
+
+// Int returns the AS as a single number
+func (as *BgpAs) Int() int {
+	if as == nil {
+		return 0
+	}
+	return as.AsXx<<16 + as.AsYy
+}
+
+// bgpInstancePath returns the path of the default BGP instance with an AS
+func bgpInstancePath(device string, as int) Path {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-ipv4-bgp-cfg:bgp").
+		Entry("instance", "default").
+		Entry("instance-as", 0).
+		Entry("four-byte-as", as)
+}
+
+// NetconfCiscoBgpNeighborURL returns netconf cisco BGP neighbor URL for a BGP instance AS
+func NetconfCiscoBgpNeighborURL(device string, as int, neighbor string) string {
+	return bgpInstancePath(device, as).
+		Child("default-vrf/bgp-entity/neighbors").
+		Entry("neighbor", neighbor).String()
+}
+
+// NetconfCiscoBgpNeighborURLOperational returns netconf cisco BGP neighbor Operational URL
+func NetconfCiscoBgpNeighborURLOperational(device string, neighbor string) string {
+	return OperationalPath().Mount(device).
+		Child("Cisco-IOS-XR-ipv4-bgp-oper:bgp/instances").
+		Entry("instance", "default").
+		Child("instance-active/default-vrf/neighbors").
+		Entry("neighbor", neighbor).String()
+}
+
+// NetconfCiscoBgpNeighborPayload forms a json payload for cisco BGP neighbor
+func NetconfCiscoBgpNeighborPayload(neighbor CiscoBgpNeighbor) (bytes.Buffer, error) {
+	payloadBody := CiscoBgpNeighborPayload{
+		Node: []CiscoBgpNeighbor{neighbor},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoBgpNeighborPayload parses json payload for cisco BGP neighbor to a struct
+func ParseNetconfCiscoBgpNeighborPayload(bodyBytes []byte) (CiscoBgpNeighbor, error) {
+	item := &CiscoBgpNeighborPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoBgpNeighbor{}, err
+	}
+
+	var neighbor CiscoBgpNeighbor = item.Node[0]
+
+	// Keep the password out of the logs
+	log.Printf("[DEBUG] Parsed Body: neighbor-address %s", neighbor.NeighborAddress)
+
+	return neighbor, nil
+}
+
+// ParseNetconfCiscoBgpNeighborOperationalPayload parses json operational payload for cisco BGP neighbor to a struct
+func ParseNetconfCiscoBgpNeighborOperationalPayload(bodyBytes []byte) (CiscoBgpNeighborOperational, error) {
+	item := &CiscoBgpNeighborOperationalPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoBgpNeighborOperational{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var neighbor CiscoBgpNeighborOperational = item.Node[0]
+	return neighbor, nil
+}
@@ -0,0 +1,27 @@
+package payload
+
+import "testing"
+
+func TestBgpAs(t *testing.T) {
+	cases := []struct {
+		as       int
+		expected BgpAs
+	}{
+		{65001, BgpAs{AsXx: 0, AsYy: 65001}},
+		{65536, BgpAs{AsXx: 1, AsYy: 0}},
+		{4200000001, BgpAs{AsXx: 64086, AsYy: 59905}},
+	}
+	for _, c := range cases {
+		as := NewBgpAs(c.as)
+		if *as != c.expected {
+			t.Errorf("%d: expected %+v, got %+v", c.as, c.expected, *as)
+		}
+		if as.Int() != c.as {
+			t.Errorf("%d: expected the AS back, got %d", c.as, as.Int())
+		}
+	}
+	// replies may hold a 4 byte AS whole in as-yy
+	if as := (&BgpAs{AsYy: 4200000001}).Int(); as != 4200000001 {
+		t.Errorf("expected a whole as-yy to be read as is, got %d", as)
+	}
+}
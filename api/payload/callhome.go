@@ -0,0 +1,63 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+)
+
+// NetconfCallhomePayload struct
+type NetconfCallhomePayload struct {
+	Node []NetconfCallhomeDevice `json:"device"`
+}
+
+// NetconfCallhomeDevice struct represents an odl-netconf-callhome-server allowed device
+type NetconfCallhomeDevice struct {
+	UniqueID    string                      `json:"unique-id"`
+	SSHHostKey  string                      `json:"ssh-host-key"`
+	Credentials *NetconfCallhomeCredentials `json:"credentials,omitempty"`
+}
+
+// NetconfCallhomeCredentials struct, the passwords are tried in order
+type NetconfCallhomeCredentials struct {
+	Username  string   `json:"username"`
+	Passwords []string `json:"passwords"`
+}
+
+// NetconfCallhomeDeviceURL returns the call-home allowed device URL
+func NetconfCallhomeDeviceURL(uniqueID string) string {
+	return ConfigPath().
+		Child("odl-netconf-callhome-server:netconf-callhome-server/allowed-devices").
+		Entry("device", uniqueID).String()
+}
+
+// NetconfCallhomeDevicePayload forms a json payload for a call-home allowed device
+func NetconfCallhomeDevicePayload(device NetconfCallhomeDevice) (bytes.Buffer, error) {
+	payloadBody := NetconfCallhomePayload{
+		Node: []NetconfCallhomeDevice{device},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCallhomeDevicePayload parses json payload for a call-home allowed device to a struct
+func ParseNetconfCallhomeDevicePayload(bodyBytes []byte) (NetconfCallhomeDevice, error) {
+	item := &NetconfCallhomePayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return NetconfCallhomeDevice{}, err
+	}
+
+	var device NetconfCallhomeDevice = item.Node[0]
+
+	// Keep the passwords out of the logs
+	log.Printf("[DEBUG] Parsed Body: unique-id %s", device.UniqueID)
+
+	return device, nil
+}
@@ -0,0 +1,51 @@
+package payload
+
+import (
+	"strings"
+)
+
+// YangModule struct is a YANG module advertised by a device
+type YangModule struct {
+	Namespace string
+	Revision  string
+	Name      string
+}
+
+// ParseYangModule parses a (namespace?revision=date)module capability, capabilities that
+// are not YANG modules, like urn:ietf:params:netconf:base:1.1, are not ok
+func ParseYangModule(capability string) (YangModule, bool) {
+	if !strings.HasPrefix(capability, "(") {
+		return YangModule{}, false
+	}
+	end := strings.Index(capability, ")")
+	if end < 0 || end == len(capability)-1 {
+		return YangModule{}, false
+	}
+
+	module := YangModule{
+		Namespace: capability[1:end],
+		Name:      capability[end+1:],
+	}
+	if i := strings.Index(module.Namespace, "?revision="); i >= 0 {
+		module.Revision = module.Namespace[i+len("?revision="):]
+		module.Namespace = module.Namespace[:i]
+	}
+	return module, true
+}
+
+// YangModules returns the YANG modules of the device keyed by module name
+func (n NetconfOperational) YangModules() map[string]YangModule {
+	modules := map[string]YangModule{}
+	if n.AvailableCapabilities == nil {
+		return modules
+	}
+	for _, capability := range n.AvailableCapabilities.AvailableCapability {
+		if module, ok := ParseYangModule(capability.Capability); ok {
+			// A module can be advertised in several revisions, keep the latest
+			if latest, found := modules[module.Name]; !found || module.Revision > latest.Revision {
+				modules[module.Name] = module
+			}
+		}
+	}
+	return modules
+}
@@ -0,0 +1,372 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrSchemaMismatch is wrapped by the errors of replies that don't have the shape of the
+// payload they are parsed into, ie an empty reply or a list sent as a container
+var ErrSchemaMismatch = errors.New("reply does not match the payload schema")
+
+// SchemaError lists the nodes of a reply that don't match its payload by their json path,
+// ie interface-configuration[0].mtus.mtu[0].mtu
+type SchemaError struct {
+	Payload string   // type the reply was parsed into
+	Missing []string // required nodes the reply doesn't have
+	Invalid []string // nodes of another type than the payload's
+	Unknown []string // members the payload has no field for
+	Absent  []string // optional nodes the reply doesn't have
+}
+
+func (e *SchemaError) Error() string {
+	problems := []string{}
+	for _, path := range e.Missing {
+		problems = append(problems, "missing "+path)
+	}
+	problems = append(problems, e.Invalid...)
+	for _, path := range e.Unknown {
+		problems = append(problems, "unknown "+path)
+	}
+	for _, path := range e.Absent {
+		problems = append(problems, "absent "+path)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Payload, ErrSchemaMismatch, strings.Join(problems, ", "))
+}
+
+// Unwrap lets errors.Is find ErrSchemaMismatch
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaMismatch
+}
+
+// Fatal reports whether the reply couldn't be parsed, replies with only unknown
+// or absent nodes are parsed without them
+func (e *SchemaError) Fatal() bool {
+	return len(e.Missing) > 0 || len(e.Invalid) > 0
+}
+
+// Unmarshal parses a json or XML reply into v. The reply is matched against the type of v
+// before it is parsed: members qualified with a module name match the field of their local
+// name, the lists at the top of v must be there with at least one entry, and nodes of the
+// wrong type are returned in a *SchemaError instead of a partly parsed payload. Members the
+// payload has no field for and optional nodes the reply leaves out don't fail the parse,
+// use UnmarshalStrict to get them as an error. Unknown members are logged as a warning,
+// absent nodes only as debug as replies leave out unset leaves and the nodes a read's
+// fields don't select
+func Unmarshal(body []byte, v interface{}) error {
+	schemaErr, err := unmarshal(body, v)
+	if err != nil {
+		return err
+	}
+	if schemaErr == nil {
+		return nil
+	}
+	if len(schemaErr.Unknown) > 0 {
+		log.Printf("[WARN] %s", &SchemaError{Payload: schemaErr.Payload, Unknown: schemaErr.Unknown})
+	}
+	if len(schemaErr.Absent) > 0 {
+		log.Printf("[DEBUG] %s", &SchemaError{Payload: schemaErr.Payload, Absent: schemaErr.Absent})
+	}
+	return nil
+}
+
+// UnmarshalStrict parses a reply like Unmarshal, but returns a *SchemaError listing the
+// members the payload has no field for and the optional nodes the reply leaves out, v
+// is parsed when that is all that doesn't match
+func UnmarshalStrict(body []byte, v interface{}) error {
+	schemaErr, err := unmarshal(body, v)
+	if err != nil {
+		return err
+	}
+	if schemaErr != nil {
+		return schemaErr
+	}
+	return nil
+}
+
+// unmarshal parses a reply into v and returns the nodes that don't match it, the error is a
+// *SchemaError and v is left unparsed when they are fatal
+func unmarshal(body []byte, v interface{}) (*SchemaError, error) {
+	t := reflect.TypeOf(v)
+	var node interface{}
+	trimmed := bytes.TrimSpace(body)
+	switch {
+	case len(trimmed) == 0:
+	case trimmed[0] == '<':
+		// XML doesn't tell lists from containers, so it is converted by the type of v
+		root, err := parseXMLElement(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		node = xmlObject([]*xmlElement{root}, t)
+	default:
+		// Numbers are kept as they were sent so they can be checked against their field
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&node); err != nil {
+			return nil, err
+		}
+	}
+
+	match := schemaMatch{}
+	conformed := match.root(node, t)
+
+	schemaErr := match.err(strings.TrimPrefix(t.String(), "*"))
+	if schemaErr != nil && schemaErr.Fatal() {
+		return nil, schemaErr
+	}
+
+	jsonBody, err := json.Marshal(conformed)
+	if err != nil {
+		return nil, err
+	}
+	return schemaErr, json.Unmarshal(jsonBody, v)
+}
+
+// schemaMatch collects the json paths of the nodes of a reply that don't match its payload
+type schemaMatch struct {
+	unknown []string // members the payload has no field for
+	absent  []string // optional nodes the reply doesn't have
+	missing []string // required nodes the reply doesn't have
+	invalid []string // nodes of another type than their field
+}
+
+// err returns the nodes that don't match a payload sorted by their path, nil when all of them do
+func (m *schemaMatch) err(payload string) *SchemaError {
+	if len(m.missing) == 0 && len(m.invalid) == 0 && len(m.unknown) == 0 && len(m.absent) == 0 {
+		return nil
+	}
+	for _, paths := range [][]string{m.missing, m.invalid, m.unknown, m.absent} {
+		sort.Strings(paths)
+	}
+	return &SchemaError{Payload: payload, Missing: m.missing, Invalid: m.invalid, Unknown: m.unknown, Absent: m.absent}
+}
+
+// unmarshalerType is implemented by fields that parse their json themselves
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// root matches the top of a reply, its lists hold the entries that were read so they are required
+func (m *schemaMatch) root(node interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return m.value(node, t, "")
+	}
+
+	object, ok := node.(map[string]interface{})
+	if node != nil && !ok {
+		return m.mismatch("reply", node, "an object")
+	}
+	conformed := m.object(object, t, "", true)
+
+	for name, value := range conformed {
+		if list, isList := value.([]interface{}); value == nil || isList && len(list) == 0 {
+			m.missing = append(m.missing, name+"[0]")
+		}
+	}
+	return conformed
+}
+
+// value matches a node with the type of its field and returns it with the member names of
+// the payload, nodes of the wrong type are left out
+func (m *schemaMatch) value(node interface{}, t reflect.Type, path string) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node == nil || reflect.PtrTo(t).Implements(unmarshalerType) {
+		return node
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return m.mismatch(path, node, "an object")
+		}
+		return m.object(object, t, path, false)
+	case reflect.Map:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return m.mismatch(path, node, "an object")
+		}
+		conformed := map[string]interface{}{}
+		for name, member := range object {
+			conformed[name] = m.value(member, t.Elem(), memberPath(path, name))
+		}
+		return conformed
+	case reflect.Slice, reflect.Array:
+		list, ok := node.([]interface{})
+		if !ok {
+			return m.mismatch(path, node, "a list")
+		}
+		conformed := make([]interface{}, len(list))
+		for i, entry := range list {
+			conformed[i] = m.value(entry, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+		return conformed
+	case reflect.String:
+		switch v := node.(type) {
+		case string:
+			return v
+		case json.Number:
+			// Unions of numbers and strings are parsed as strings
+			return v.String()
+		}
+		return m.mismatch(path, node, "a string")
+	case reflect.Bool:
+		switch v := node.(type) {
+		case bool:
+			return v
+		case string:
+			if v == "true" || v == "false" {
+				return v == "true"
+			}
+		}
+		return m.mismatch(path, node, "a boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		// 64 bit integers and decimals are strings in YANG json
+		text := ""
+		switch v := node.(type) {
+		case json.Number:
+			text = v.String()
+		case string:
+			text = v
+		}
+		if numberFits(text, t) {
+			return json.Number(text)
+		}
+		if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
+			return m.mismatch(path, node, "a decimal")
+		}
+		return m.mismatch(path, node, "an integer")
+	}
+	return node
+}
+
+// object matches the members of a json object with the fields of a struct
+func (m *schemaMatch) object(object map[string]interface{}, t reflect.Type, path string, required bool) map[string]interface{} {
+	conformed := map[string]interface{}{}
+	for member, value := range object {
+		field, name, found := memberField(t, member)
+		if !found {
+			m.unknown = append(m.unknown, memberPath(path, member))
+			continue
+		}
+		conformed[name] = m.value(value, field.Type, memberPath(path, member))
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty := fieldName(field)
+		if name == "" {
+			continue
+		}
+		if _, found := conformed[name]; found {
+			continue
+		}
+		switch {
+		case required:
+			m.missing = append(m.missing, memberPath(path, name))
+		case !omitempty && field.Type.Kind() != reflect.Ptr:
+			m.absent = append(m.absent, memberPath(path, name))
+		}
+	}
+	return conformed
+}
+
+// mismatch records a node of the wrong type, it is left out of the parsed payload
+func (m *schemaMatch) mismatch(path string, node interface{}, expected string) interface{} {
+	found := "a number"
+	switch node.(type) {
+	case map[string]interface{}:
+		found = "an object"
+	case []interface{}:
+		found = "a list"
+	case string:
+		found = "a string"
+	case bool:
+		found = "a boolean"
+	}
+	m.invalid = append(m.invalid, fmt.Sprintf("%s is %s instead of %s", path, found, expected))
+	return nil
+}
+
+// memberField returns the field of a json member and its name in the payload. Members match
+// the field of their local name when either is qualified with a module name, as RFC 7951 only
+// qualifies the members whose module differs from their parent's
+func memberField(t reflect.Type, member string) (reflect.StructField, string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _ := fieldName(field); name == member {
+			return field, name, true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _ := fieldName(field); name != "" && localName(name) == localName(member) {
+			return field, name, true
+		}
+	}
+	return reflect.StructField{}, "", false
+}
+
+// fieldName returns the json name of a struct field, empty for fields json skips
+func fieldName(field reflect.StructField) (name string, omitempty bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := strings.Split(field.Tag.Get("json"), ",")
+	if tag[0] == "-" {
+		return "", false
+	}
+	name = tag[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range tag[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// localName returns a member name without its module name
+func localName(name string) string {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// memberPath appends a member to a json path
+func memberPath(path string, member string) string {
+	if path == "" {
+		return member
+	}
+	return path + "." + member
+}
+
+// numberFits reports whether a number parses as the type of its field
+func numberFits(text string, t reflect.Type) bool {
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(text, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(text, 10, t.Bits())
+	default:
+		_, err = strconv.ParseFloat(text, t.Bits())
+	}
+	return err == nil
+}
@@ -0,0 +1,222 @@
+package payload
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testDecodePayload is a reply with a list of entries at its top like the payloads of the provider
+type testDecodePayload struct {
+	Node []testDecodeEntry `json:"interface-configuration"`
+}
+
+type testDecodeEntry struct {
+	Name        string           `json:"interface-name"`
+	Description string           `json:"description"`
+	Shutdown    *Empty           `json:"shutdown,omitempty"`
+	Enabled     bool             `json:"enabled,omitempty"`
+	Mtus        *testDecodeMtus  `json:"mtus,omitempty"`
+	Speed       uint8            `json:"speed,omitempty"`
+	Bandwidth   int64            `json:"bandwidth,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Extra       map[string]int32 `json:"extra,omitempty"`
+}
+
+type testDecodeMtus struct {
+	Mtu []testDecodeMtu `json:"mtu"`
+}
+
+type testDecodeMtu struct {
+	Owner string `json:"owner"`
+	Mtu   uint32 `json:"mtu"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected testDecodeEntry
+	}{
+		{
+			name: "json",
+			body: `{"interface-configuration":[{"interface-name":"Gi0/0/0/1","description":"uplink","shutdown":[null],"enabled":true,` +
+				`"mtus":{"mtu":[{"owner":"GigabitEthernet","mtu":9000}]},"speed":10,"bandwidth":"10000000000","tags":["a","b"],"extra":{"x":1}}]}`,
+			expected: testDecodeEntry{Name: "Gi0/0/0/1", Description: "uplink", Shutdown: &Empty{}, Enabled: true,
+				Mtus:  &testDecodeMtus{Mtu: []testDecodeMtu{{Owner: "GigabitEthernet", Mtu: 9000}}},
+				Speed: 10, Bandwidth: 10000000000, Tags: []string{"a", "b"}, Extra: map[string]int32{"x": 1}},
+		},
+		{
+			name: "module qualified members",
+			body: `{"Cisco-IOS-XR-ifmgr-cfg:interface-configuration":[{"interface-name":"Gi0/0/0/1","Cisco-IOS-XR-ifmgr-cfg:description":"uplink",` +
+				`"Cisco-IOS-XR-ifmgr-cfg:mtus":{"mtu":[{"owner":"GigabitEthernet","mtu":"9000"}]}}]}`,
+			expected: testDecodeEntry{Name: "Gi0/0/0/1", Description: "uplink",
+				Mtus: &testDecodeMtus{Mtu: []testDecodeMtu{{Owner: "GigabitEthernet", Mtu: 9000}}}},
+		},
+		{
+			name:     "strings of booleans and numbers",
+			body:     `{"interface-configuration":[{"interface-name":1,"description":"","enabled":"true","speed":"10"}]}`,
+			expected: testDecodeEntry{Name: "1", Enabled: true, Speed: 10},
+		},
+		{
+			name: "xml",
+			body: `<interface-configuration xmlns="http://cisco.com/ns/yang/Cisco-IOS-XR-ifmgr-cfg">` +
+				`<interface-name>Gi0/0/0/1</interface-name><description>uplink</description><shutdown/>` +
+				`<mtus><mtu><owner>GigabitEthernet</owner><mtu>9000</mtu></mtu></mtus><tags>a</tags>` +
+				`</interface-configuration>`,
+			expected: testDecodeEntry{Name: "Gi0/0/0/1", Description: "uplink", Shutdown: &Empty{},
+				Mtus: &testDecodeMtus{Mtu: []testDecodeMtu{{Owner: "GigabitEthernet", Mtu: 9000}}}, Tags: []string{"a"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			item := testDecodePayload{}
+			if err := UnmarshalStrict([]byte(c.body), &item); err != nil {
+				t.Fatal(err)
+			}
+			if len(item.Node) != 1 || !reflect.DeepEqual(item.Node[0], c.expected) {
+				t.Errorf("expected\n%+v\ngot\n%+v", c.expected, item.Node)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSchemaErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected SchemaError
+	}{
+		{
+			name:     "empty reply",
+			body:     "",
+			expected: SchemaError{Missing: []string{"interface-configuration"}},
+		},
+		{
+			name:     "empty list",
+			body:     `{"interface-configuration":[]}`,
+			expected: SchemaError{Missing: []string{"interface-configuration[0]"}},
+		},
+		{
+			name:     "reply of another payload",
+			body:     `{"vrf":[{"vrf-name":"blue"}]}`,
+			expected: SchemaError{Missing: []string{"interface-configuration"}, Unknown: []string{"vrf"}},
+		},
+		{
+			name:     "list sent as a container",
+			body:     `{"interface-configuration":{"interface-name":"Gi0/0/0/1","description":""}}`,
+			expected: SchemaError{Missing: []string{"interface-configuration[0]"}, Invalid: []string{"interface-configuration is an object instead of a list"}},
+		},
+		{
+			name: "nodes of the wrong type",
+			body: `{"interface-configuration":[{"interface-name":"Gi0/0/0/1","description":"","enabled":"yes","speed":256,` +
+				`"bandwidth":1.5,"mtus":[],"tags":"a","extra":{"x":"y"}}]}`,
+			expected: SchemaError{Invalid: []string{
+				"interface-configuration[0].bandwidth is a number instead of an integer",
+				"interface-configuration[0].enabled is a string instead of a boolean",
+				"interface-configuration[0].extra.x is a string instead of an integer",
+				"interface-configuration[0].mtus is a list instead of an object",
+				"interface-configuration[0].speed is a number instead of an integer",
+				"interface-configuration[0].tags is a string instead of a list",
+			}},
+		},
+		{
+			name:     "reply that isn't an object",
+			body:     `[{"interface-name":"Gi0/0/0/1"}]`,
+			expected: SchemaError{Invalid: []string{"reply is a list instead of an object"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			item := testDecodePayload{}
+			err := Unmarshal([]byte(c.body), &item)
+			if !errors.Is(err, ErrSchemaMismatch) {
+				t.Fatalf("expected ErrSchemaMismatch, got %v", err)
+			}
+			schemaErr := &SchemaError{}
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("expected a *SchemaError, got %T", err)
+			}
+			c.expected.Payload = "payload.testDecodePayload"
+			if !reflect.DeepEqual(*schemaErr, c.expected) {
+				t.Errorf("expected\n%+v\ngot\n%+v", c.expected, *schemaErr)
+			}
+			if !schemaErr.Fatal() {
+				t.Error("expected the schema error to be fatal")
+			}
+			if item.Node != nil {
+				t.Errorf("expected the payload to be left unparsed, got %+v", item.Node)
+			}
+		})
+	}
+}
+
+func TestUnmarshalUnknownAndAbsent(t *testing.T) {
+	body := []byte(`{"interface-configuration":[{"interface-name":"Gi0/0/0/1","active":"act",` +
+		`"mtus":{"mtu":[{"owner":"GigabitEthernet","mtu":9000,"Cisco-IOS-XR-ifmgr-cfg:unit":"bytes"}]}}]}`)
+	expected := testDecodeEntry{Name: "Gi0/0/0/1", Mtus: &testDecodeMtus{Mtu: []testDecodeMtu{{Owner: "GigabitEthernet", Mtu: 9000}}}}
+
+	item := testDecodePayload{}
+	if err := Unmarshal(body, &item); err != nil {
+		t.Fatalf("expected unknown and absent nodes not to fail the parse, got %v", err)
+	}
+	if len(item.Node) != 1 || !reflect.DeepEqual(item.Node[0], expected) {
+		t.Errorf("expected\n%+v\ngot\n%+v", expected, item.Node)
+	}
+
+	strict := testDecodePayload{}
+	err := UnmarshalStrict(body, &strict)
+	schemaErr := &SchemaError{}
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected a *SchemaError, got %v", err)
+	}
+	if schemaErr.Fatal() {
+		t.Errorf("expected unknown and absent nodes not to be fatal, got %v", schemaErr)
+	}
+	if unknown := []string{"interface-configuration[0].active", "interface-configuration[0].mtus.mtu[0].Cisco-IOS-XR-ifmgr-cfg:unit"}; !reflect.DeepEqual(schemaErr.Unknown, unknown) {
+		t.Errorf("expected unknown %v, got %v", unknown, schemaErr.Unknown)
+	}
+	if absent := []string{"interface-configuration[0].description"}; !reflect.DeepEqual(schemaErr.Absent, absent) {
+		t.Errorf("expected absent %v, got %v", absent, schemaErr.Absent)
+	}
+	if !reflect.DeepEqual(strict, item) {
+		t.Errorf("expected the strict parse to parse the payload, got %+v", strict.Node)
+	}
+	expectedMessage := "payload.testDecodePayload reply does not match the payload schema: " +
+		"unknown interface-configuration[0].active, unknown interface-configuration[0].mtus.mtu[0].Cisco-IOS-XR-ifmgr-cfg:unit, " +
+		"absent interface-configuration[0].description"
+	if err.Error() != expectedMessage {
+		t.Errorf("expected %q, got %q", expectedMessage, err.Error())
+	}
+}
+
+// Replies leave out unset leaves and the nodes a read's fields don't select, so only unknown
+// members are warned about
+func TestUnmarshalLogsAbsentAsDebug(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	item := testDecodePayload{}
+	if err := Unmarshal([]byte(`{"interface-configuration":[{"interface-name":"Gi0/0/0/1","active":"act"}]}`), &item); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logged.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a warning and a debug line, got %q", logged.String())
+	}
+	if !strings.Contains(lines[0], "[WARN]") || !strings.Contains(lines[0], "unknown interface-configuration[0].active") ||
+		strings.Contains(lines[0], "absent") {
+		t.Errorf("expected only the unknown member as a warning, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "[DEBUG]") || !strings.Contains(lines[1], "absent interface-configuration[0].description") {
+		t.Errorf("expected the absent node as debug, got %q", lines[1])
+	}
+}
@@ -0,0 +1,363 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Encoding is a media type payloads are exchanged with the controller in. Payloads are always
+// built as json, an encoding converts them before they are sent and decodes the replies
+type Encoding interface {
+	// MediaType is the Content-Type and Accept of requests in the encoding
+	MediaType() string
+	// Encode converts a json payload, module is the YANG module of the node the payload is sent to
+	Encode(jsonBody bytes.Buffer, module string) (bytes.Buffer, error)
+}
+
+// JSON sends payloads as they are built
+var JSON Encoding = jsonEncoding{}
+
+// XML converts payloads to XML with the namespaces of their YANG modules
+var XML Encoding = xmlEncoding{}
+
+type jsonEncoding struct{}
+
+func (jsonEncoding) MediaType() string {
+	return "application/json"
+}
+
+func (jsonEncoding) Encode(jsonBody bytes.Buffer, module string) (bytes.Buffer, error) {
+	return jsonBody, nil
+}
+
+type xmlEncoding struct{}
+
+func (xmlEncoding) MediaType() string {
+	return "application/yang-data+xml"
+}
+
+// moduleNamespaces are the namespaces of the YANG modules whose payloads are hand-written. The
+// namespaces of the modules in the go:generate line are generated in yangModuleNamespaces, a
+// module only has to be added here when its payloads aren't generated. Cisco modules are named
+// after their namespace and aren't listed
+var moduleNamespaces = map[string]string{
+	"netconf-keystore":            "urn:opendaylight:netconf:keystore",
+	"odl-netconf-callhome-server": "urn:opendaylight:params:xml:ns:yang:netconf-callhome-server",
+	"ietf-yang-patch":             "urn:ietf:params:xml:ns:yang:ietf-yang-patch",
+}
+
+// ModuleNamespace returns the XML namespace of a YANG module
+func ModuleNamespace(module string) (string, bool) {
+	if namespace, ok := yangModuleNamespaces[module]; ok {
+		return namespace, true
+	}
+	if strings.HasPrefix(module, "Cisco-IOS-XR-") {
+		return "http://cisco.com/ns/yang/" + module, true
+	}
+	namespace, ok := moduleNamespaces[module]
+	return namespace, ok
+}
+
+// namespaceModule returns the YANG module of an XML namespace
+func namespaceModule(namespace string) (string, bool) {
+	if strings.HasPrefix(namespace, "http://cisco.com/ns/yang/") {
+		return strings.TrimPrefix(namespace, "http://cisco.com/ns/yang/"), true
+	}
+	for _, namespaces := range []map[string]string{yangModuleNamespaces, moduleNamespaces} {
+		for module, ns := range namespaces {
+			if ns == namespace {
+				return module, true
+			}
+		}
+	}
+	return "", false
+}
+
+// PathModule returns the YANG module of the last module qualified node of a url, which
+// is the module of a payload sent to it unless the payload qualifies its nodes itself
+func PathModule(url string) string {
+	module := ""
+	for _, segment := range strings.Split(url, "/") {
+		if i := strings.Index(segment, ":"); i > 0 && !strings.Contains(segment, "%") && segment != "yang-ext:mount" {
+			module = segment[:i]
+		}
+	}
+	return module
+}
+
+// Encode converts json to XML as RFC 7951 maps them: members are elements, arrays are repeated
+// elements, [null] is an empty leaf and module qualified names and identities get namespaces
+func (xmlEncoding) Encode(jsonBody bytes.Buffer, module string) (bytes.Buffer, error) {
+	buf := bytes.Buffer{}
+	if jsonBody.Len() == 0 {
+		return buf, nil
+	}
+
+	decoder := json.NewDecoder(&jsonBody)
+	decoder.UseNumber()
+	encoder := xml.NewEncoder(&buf)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return buf, err
+	}
+	if token != json.Delim('{') {
+		return buf, fmt.Errorf("payload is not a json object")
+	}
+	if err := encodeXMLMembers(decoder, encoder, module, ""); err != nil {
+		return buf, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// encodeXMLMembers writes the members of a json object up to its closing brace as elements,
+// the order of the members is kept so list keys stay in front
+func encodeXMLMembers(decoder *json.Decoder, encoder *xml.Encoder, module string, parentNamespace string) error {
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		name := token.(string)
+
+		elementModule := module
+		if i := strings.Index(name, ":"); i >= 0 {
+			elementModule, name = name[:i], name[i+1:]
+		}
+		namespace, ok := ModuleNamespace(elementModule)
+		if !ok {
+			return fmt.Errorf("no XML namespace for YANG module %q of %s", elementModule, name)
+		}
+
+		start := xml.StartElement{Name: xml.Name{Local: name}}
+		if namespace != parentNamespace {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: namespace})
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token == json.Delim('[') {
+			// [null] is an empty leaf, other arrays are lists and leaf-lists
+			empty := true
+			for decoder.More() {
+				value, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				if value == nil && empty {
+					continue
+				}
+				empty = false
+				if err := encodeXMLValue(decoder, encoder, start, value, elementModule, namespace); err != nil {
+					return err
+				}
+			}
+			if _, err := decoder.Token(); err != nil {
+				return err
+			}
+			if empty {
+				if err := encoder.EncodeToken(start); err != nil {
+					return err
+				}
+				if err := encoder.EncodeToken(start.End()); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := encodeXMLValue(decoder, encoder, start, token, elementModule, namespace); err != nil {
+			return err
+		}
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+// encodeXMLValue writes a single value as an element
+func encodeXMLValue(decoder *json.Decoder, encoder *xml.Encoder, start xml.StartElement, value json.Token, module string, namespace string) error {
+	text := ""
+	switch v := value.(type) {
+	case json.Delim:
+		if v != '{' {
+			return fmt.Errorf("nested arrays are not valid YANG json in %s", start.Name.Local)
+		}
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if err := encodeXMLMembers(decoder, encoder, module, namespace); err != nil {
+			return err
+		}
+		return encoder.EncodeToken(start.End())
+	case string:
+		text = v
+		// Identities are qualified with their module, XML declares a prefix for it
+		if i := strings.Index(v, ":"); i > 0 {
+			if identityNamespace, ok := ModuleNamespace(v[:i]); ok {
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + v[:i]}, Value: identityNamespace})
+			}
+		}
+	case json.Number:
+		text = v.String()
+	case bool:
+		text = fmt.Sprint(v)
+	case nil:
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if text != "" {
+		if err := encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlElement is a parsed XML element, prefixes are the namespace prefixes in scope of it
+type xmlElement struct {
+	name     xml.Name
+	prefixes map[string]string
+	children []*xmlElement
+	text     string
+}
+
+// parseXMLElement parses the root element of a document
+func parseXMLElement(body []byte) (*xmlElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var stack []*xmlElement
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("XML reply has no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{name: t.Name, prefixes: map[string]string{}}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+				for prefix, namespace := range parent.prefixes {
+					element.prefixes[prefix] = namespace
+				}
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					element.prefixes[attr.Name.Local] = attr.Value
+				}
+			}
+			stack = append(stack, element)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		case xml.EndElement:
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return element, nil
+			}
+		}
+	}
+}
+
+// xmlObject converts elements to the json object of a struct or map type
+func xmlObject(elements []*xmlElement, t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	object := map[string]interface{}{}
+
+	switch t.Kind() {
+	case reflect.Map:
+		grouped := map[string][]*xmlElement{}
+		for _, element := range elements {
+			grouped[element.name.Local] = append(grouped[element.name.Local], element)
+		}
+		for name, group := range grouped {
+			object[name] = xmlValue(group, t.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			local := name
+			if i := strings.Index(name, ":"); i >= 0 {
+				local = name[i+1:]
+			}
+
+			var matching []*xmlElement
+			for _, element := range elements {
+				if element.name.Local == local {
+					matching = append(matching, element)
+				}
+			}
+			if len(matching) > 0 {
+				object[name] = xmlValue(matching, field.Type)
+			}
+		}
+	}
+	return object
+}
+
+// emptyType is the type of empty leaves
+var emptyType = reflect.TypeOf(Empty{})
+
+// rawMessageType is the type of values that are kept as json
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// xmlValue converts the elements of a node to the json value of its type, slices are lists
+func xmlValue(elements []*xmlElement, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && t != rawMessageType {
+		values := []interface{}{}
+		for _, element := range elements {
+			values = append(values, xmlValue([]*xmlElement{element}, t.Elem()))
+		}
+		return values
+	}
+
+	element := elements[0]
+	text := strings.TrimSpace(element.text)
+	switch {
+	case t == emptyType:
+		return []interface{}{nil}
+	case t.Kind() == reflect.Struct || t.Kind() == reflect.Map:
+		return xmlObject(element.children, t)
+	case t.Kind() == reflect.Bool:
+		return text == "true"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		if text == "" {
+			return nil
+		}
+		return json.Number(text)
+	}
+
+	// Identities get the module of their prefix instead of the prefix
+	if i := strings.Index(text, ":"); i > 0 {
+		if namespace, ok := element.prefixes[text[:i]]; ok {
+			if module, ok := namespaceModule(namespace); ok {
+				return module + text[i:]
+			}
+		}
+	}
+	return text
+}
@@ -0,0 +1,247 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// testAddressFamily is a list entry with an identity and an empty leaf
+type testAddressFamily struct {
+	AfName   string `json:"af-name"`
+	Activate *Empty `json:"activate,omitempty"`
+}
+
+type testAddressFamilyPayload struct {
+	Node []testAddressFamily `json:"address-family"`
+}
+
+func parseTestAddressFamily(body []byte) (testAddressFamily, error) {
+	item := testAddressFamilyPayload{}
+	if err := Unmarshal(body, &item); err != nil {
+		return testAddressFamily{}, err
+	}
+	return item.Node[0], nil
+}
+
+// testSample is a payload and the files in testdata it is sent and read back as
+type testSample struct {
+	name    string
+	module  string                            // module of the url the payload is sent to
+	encode  func() (bytes.Buffer, error)      // builds the json payload
+	parse   func([]byte) (interface{}, error) // parses a reply
+	payload interface{}                       // what the reply parses into
+}
+
+func testSamples() []testSample {
+	keepaliveDelay := uint32(120)
+	override := true
+	sleepFactor := 1.5
+	tcpOnly := false
+	vlan := InterfaceConfiguration{
+		Active:                   "pre",
+		InterfaceName:            "GigabitEthernet0/0/0/1.100",
+		Description:              "customer a",
+		InterfaceModeNonPhysical: InterfaceModeEnumL2Transport,
+		Mtus: &InterfaceConfigurationMtus{
+			Mtu: []InterfaceConfigurationMtusMtu{{Owner: "sub_vlan", Mtu: 9216}},
+		},
+		EthernetService: &InterfaceConfigurationEthernetService{
+			Encapsulation: &InterfaceConfigurationEthernetServiceEncapsulation{OuterTagType: MatchMatchUntagged},
+			Rewrite: &InterfaceConfigurationEthernetServiceRewrite{
+				RewriteType:   RewritePush2,
+				OuterTagType:  MatchMatchDot1q,
+				OuterTagValue: 100,
+				InnerTagType:  MatchMatchDot1q,
+				InnerTagValue: 200,
+			},
+		},
+		Qos: NewInterfaceQos("in", "out"),
+	}
+	isis := CiscoIsisInterface{
+		InterfaceName: "Loopback0",
+		Running:       &Empty{},
+		PointToPoint:  &Empty{},
+		State:         "passive",
+		InterfaceAfs: &IsisInterfaceAfs{InterfaceAf: []IsisInterfaceAf{
+			{AfName: "ipv4", SafName: "unicast", InterfaceAfData: IsisInterfaceAfData{
+				Running: &Empty{},
+				Metrics: &IsisMetrics{Metric: []IsisMetric{{Level: "not-set", Metric: 10}}},
+			}},
+			{AfName: "ipv6", SafName: "unicast", InterfaceAfData: IsisInterfaceAfData{Running: &Empty{}}},
+		}},
+	}
+	node := Netconf{
+		NodeId:         "r1",
+		Host:           "192.0.2.1",
+		Port:           830,
+		Username:       "admin",
+		Password:       "secret",
+		TcpOnly:        &tcpOnly,
+		KeepaliveDelay: &keepaliveDelay,
+		SleepFactor:    &sleepFactor,
+		YangModuleCapabilities: &NetconfYangModuleCapabilities{
+			Override: &override,
+			Capability: []string{
+				"http://cisco.com/ns/yang/Cisco-IOS-XR-ifmgr-cfg?module=Cisco-IOS-XR-ifmgr-cfg&revision=2017-09-07",
+				"http://cisco.com/ns/yang/Cisco-IOS-XR-l2vpn-cfg?module=Cisco-IOS-XR-l2vpn-cfg&revision=2017-06-26",
+			},
+		},
+	}
+	family := testAddressFamily{AfName: "Cisco-IOS-XR-ipv4-bgp-datatypes:ipv4-unicast", Activate: &Empty{}}
+
+	return []testSample{
+		{
+			name:    "cisco_vlan",
+			module:  "Cisco-IOS-XR-ifmgr-cfg",
+			encode:  func() (bytes.Buffer, error) { return NetconfCiscoVlanPayload(vlan) },
+			parse:   func(body []byte) (interface{}, error) { return ParseNetconfCiscoVlanPayload(body) },
+			payload: vlan,
+		},
+		{
+			name:    "cisco_isis_interface",
+			module:  "Cisco-IOS-XR-clns-isis-cfg",
+			encode:  func() (bytes.Buffer, error) { return NetconfCiscoIsisInterfacePayload(isis) },
+			parse:   func(body []byte) (interface{}, error) { return ParseNetconfCiscoIsisInterfacePayload(body) },
+			payload: isis,
+		},
+		{
+			name:    "netconf_node",
+			module:  "network-topology",
+			encode:  func() (bytes.Buffer, error) { return NetconfMountPayload(node) },
+			parse:   func(body []byte) (interface{}, error) { return ParseNetconfMountPayload(body) },
+			payload: node,
+		},
+		{
+			name:   "identity",
+			module: "Cisco-IOS-XR-ipv4-bgp-cfg",
+			encode: func() (bytes.Buffer, error) {
+				buf := bytes.Buffer{}
+				err := json.NewEncoder(&buf).Encode(testAddressFamilyPayload{Node: []testAddressFamily{family}})
+				return buf, err
+			},
+			parse:   func(body []byte) (interface{}, error) { return parseTestAddressFamily(body) },
+			payload: family,
+		},
+	}
+}
+
+func readSample(t *testing.T, name string) []byte {
+	body, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// betweenElements is the indentation of XML samples
+var betweenElements = regexp.MustCompile(`>\s+<`)
+
+func TestEncodeSamples(t *testing.T) {
+	for _, sample := range testSamples() {
+		t.Run(sample.name, func(t *testing.T) {
+			jsonBody, err := sample.encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var encoded, expected interface{}
+			if err := json.Unmarshal(jsonBody.Bytes(), &encoded); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(readSample(t, sample.name+".json"), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(encoded, expected) {
+				t.Errorf("json payload differs from %s.json:\n%s", sample.name, jsonBody.String())
+			}
+
+			xmlBody, err := XML.Encode(jsonBody, sample.module)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedXML := betweenElements.ReplaceAllString(strings.TrimSpace(string(readSample(t, sample.name+".xml"))), "><")
+			if xmlBody.String() != expectedXML {
+				t.Errorf("XML payload differs from %s.xml:\n%s", sample.name, xmlBody.String())
+			}
+		})
+	}
+}
+
+func TestParseSamples(t *testing.T) {
+	for _, sample := range testSamples() {
+		for _, extension := range []string{".json", ".xml"} {
+			t.Run(sample.name+extension, func(t *testing.T) {
+				parsed, err := sample.parse(readSample(t, sample.name+extension))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(parsed, sample.payload) {
+					t.Errorf("expected %s%s to parse into\n%+v\ngot\n%+v", sample.name, extension, sample.payload, parsed)
+				}
+			})
+		}
+	}
+}
+
+// Replies of the controller declare their own prefixes for namespaces and identities
+func TestParsePrefixedXML(t *testing.T) {
+	family, err := parseTestAddressFamily(readSample(t, "identity_prefixed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := testAddressFamily{AfName: "Cisco-IOS-XR-ipv4-bgp-datatypes:ipv4-unicast", Activate: &Empty{}}
+	if !reflect.DeepEqual(family, expected) {
+		t.Errorf("expected the identity to be qualified with its module, got %+v", family)
+	}
+
+	for _, name := range []string{"netconf_node_operational.json", "netconf_node_operational.xml"} {
+		t.Run(name, func(t *testing.T) {
+			node, err := ParseNetconfOperationalMountPayload(readSample(t, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := NetconfOperational{
+				Name:      "r1",
+				IPAddress: "192.0.2.1",
+				Port:      830,
+				Status:    "connected",
+				AvailableCapabilities: &AvailableCapabilities{AvailableCapability: []AvailableCapability{
+					{Capability: "(http://cisco.com/ns/yang/Cisco-IOS-XR-ifmgr-cfg?revision=2017-09-07)Cisco-IOS-XR-ifmgr-cfg", CapabilityOrigin: "device-advertised"},
+					{Capability: "urn:ietf:params:netconf:base:1.1", CapabilityOrigin: "device-advertised"},
+				}},
+			}
+			if !reflect.DeepEqual(node, expected) {
+				t.Errorf("expected\n%+v\ngot\n%+v", expected, node)
+			}
+		})
+	}
+}
+
+func TestModuleNamespace(t *testing.T) {
+	cases := []struct {
+		module    string
+		namespace string
+	}{
+		{"netconf-node-topology", "urn:opendaylight:netconf-node-topology"}, // generated
+		{"Cisco-IOS-XR-ipv4-bgp-cfg", "http://cisco.com/ns/yang/Cisco-IOS-XR-ipv4-bgp-cfg"},
+		{"netconf-keystore", "urn:opendaylight:netconf:keystore"},
+	}
+	for _, c := range cases {
+		namespace, ok := ModuleNamespace(c.module)
+		if !ok || namespace != c.namespace {
+			t.Errorf("%s: expected %s, got %q", c.module, c.namespace, namespace)
+		}
+		if module, ok := namespaceModule(c.namespace); !ok || module != c.module {
+			t.Errorf("%s: expected the module of its namespace, got %q", c.module, module)
+		}
+	}
+	if _, ok := ModuleNamespace("unknown"); ok {
+		t.Error("expected no namespace for an unknown module")
+	}
+}
@@ -0,0 +1,45 @@
+package payload
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The structs of yang_types.go are generated from the YANG modules in the yang directory, see
+// Generating payloads in the README. The modules there are trimmed to the nodes the provider
+// configures, copy more of them or their full versions into it before running go generate
+
+//go:generate go run ../../tools/yanggen -dir yang -out yang_types.go -module Cisco-IOS-XR-ifmgr-cfg -module Cisco-IOS-XR-l2vpn-cfg -module Cisco-IOS-XR-l2-eth-infra-cfg -module Cisco-IOS-XR-infra-rsi-cfg -module Cisco-IOS-XR-qos-ma-cfg -module network-topology -module netconf-node-topology -root /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration=InterfaceConfiguration -root /Cisco-IOS-XR-l2vpn-cfg:l2vpn/database/flexible-xconnect-service-table/vlan-aware-flexible-xconnect-services/vlan-aware-flexible-xconnect-service=VlanAwareFlexibleXconnectService -root /network-topology:network-topology/topology/node=Netconf
+
+// YangRestriction returns the range or length a generated struct keeps in the yang tag of a
+// field, ie range 64 65535 for the Mtu of InterfaceConfigurationMtusMtu, so the attributes of
+// resources are validated as the model restricts them. It panics when the field has no single
+// range, restrictions are only read when the provider's schema is built
+func YangRestriction(v interface{}, field string) (restriction string, min int, max int) {
+	typ := reflect.TypeOf(v)
+	f, ok := typ.FieldByName(field)
+	if !ok {
+		panic(fmt.Sprintf("%s has no field %s", typ, field))
+	}
+	tag, ok := f.Tag.Lookup("yang")
+	if !ok {
+		panic(fmt.Sprintf("%s.%s has no range or length", typ, field))
+	}
+
+	restriction, bounds := tag, ""
+	if i := strings.Index(tag, "="); i >= 0 {
+		restriction, bounds = tag[:i], tag[i+1:]
+	}
+	parts := strings.Split(bounds, "..")
+	if len(parts) > 2 {
+		panic(fmt.Sprintf("%s.%s has %s %s, which isn't a single range", typ, field, restriction, bounds))
+	}
+	min, errMin := strconv.Atoi(parts[0])
+	max, errMax := strconv.Atoi(parts[len(parts)-1])
+	if errMin != nil || errMax != nil {
+		panic(fmt.Sprintf("%s.%s has %s %s, which isn't a single range", typ, field, restriction, bounds))
+	}
+	return restriction, min, max
+}
@@ -0,0 +1,40 @@
+package payload
+
+import "testing"
+
+func TestYangRestriction(t *testing.T) {
+	tests := []struct {
+		name        string
+		v           interface{}
+		field       string
+		restriction string
+		min         int
+		max         int
+	}{
+		{"range", InterfaceConfigurationMtusMtu{}, "Mtu", "range", 64, 65535},
+		{"range of a key", VlanAwareFlexibleXconnectService{}, "Eviid", "range", 1, 65534},
+		{"length", InterfaceConfiguration{}, "Vrf", "length", 1, 32},
+		{"range of a typedef", Netconf{}, "Port", "range", 0, 65535},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			restriction, min, max := YangRestriction(test.v, test.field)
+			if restriction != test.restriction || min != test.min || max != test.max {
+				t.Errorf("expected %s %d..%d, got %s %d..%d", test.restriction, test.min, test.max, restriction, min, max)
+			}
+		})
+	}
+}
+
+func TestYangRestrictionWithoutTag(t *testing.T) {
+	for _, field := range []string{"Description", "Unknown"} {
+		t.Run(field, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for %s", field)
+				}
+			}()
+			YangRestriction(InterfaceConfiguration{}, field)
+		})
+	}
+}
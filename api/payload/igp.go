@@ -0,0 +1,165 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+// CiscoIsisInterfacePayload struct
+type CiscoIsisInterfacePayload struct {
+	Node []CiscoIsisInterface `json:"interface"`
+}
+
+// CiscoIsisInterface struct represents a Cisco-IOS-XR-clns-isis-cfg instance interface
+type CiscoIsisInterface struct {
+	InterfaceName string            `json:"interface-name"`
+	Running       *Empty            `json:"running,omitempty"`
+	PointToPoint  *Empty            `json:"point-to-point,omitempty"`
+	State         string            `json:"state,omitempty"`
+	Bfd           *IsisBfd          `json:"bfd,omitempty"`
+	InterfaceAfs  *IsisInterfaceAfs `json:"interface-afs,omitempty"`
+}
+
+// IsisBfd struct
+type IsisBfd struct {
+	EnableIpv4 bool `json:"enable-ipv4,omitempty"`
+	EnableIpv6 bool `json:"enable-ipv6,omitempty"`
+	Interval   int  `json:"interval,omitempty"`
+	Multiplier int  `json:"multiplier,omitempty"`
+}
+
+// IsisInterfaceAfs struct
+type IsisInterfaceAfs struct {
+	InterfaceAf []IsisInterfaceAf `json:"interface-af"`
+}
+
+// IsisInterfaceAf struct
+type IsisInterfaceAf struct {
+	AfName          string              `json:"af-name"`
+	SafName         string              `json:"saf-name"`
+	InterfaceAfData IsisInterfaceAfData `json:"interface-af-data"`
+}
+
+// IsisInterfaceAfData struct
+type IsisInterfaceAfData struct {
+	Running *Empty       `json:"running,omitempty"`
+	Metrics *IsisMetrics `json:"metrics,omitempty"`
+}
+
+// IsisMetrics struct
+type IsisMetrics struct {
+	Metric []IsisMetric `json:"metric"`
+}
+
+// IsisMetric struct, level not-set applies the metric to both levels
+type IsisMetric struct {
+	Level  string `json:"level"`
+	Metric int    `json:"metric"`
+}
+
+// CiscoOspfInterfacePayload struct
+type CiscoOspfInterfacePayload struct {
+	Node []CiscoOspfInterface `json:"name-scope"`
+}
+
+// CiscoOspfInterface struct represents a Cisco-IOS-XR-ipv4-ospf-cfg area interface
+type CiscoOspfInterface struct {
+	InterfaceName string   `json:"interface-name"`
+	Running       *Empty   `json:"running,omitempty"`
+	Cost          int      `json:"cost,omitempty"`
+	NetworkType   string   `json:"network-type,omitempty"`
+	Passive       bool     `json:"passive,omitempty"`
+	Bfd           *OspfBfd `json:"bfd,omitempty"`
+}
+
+// OspfBfd struct
+type OspfBfd struct {
+	FastDetectMode      string `json:"fast-detect-mode,omitempty"`
+	Interval            int    `json:"interval,omitempty"`
+	DetectionMultiplier int    `json:"detection-multiplier,omitempty"`
+}
+
+// NetconfCiscoIsisInterfaceURL returns netconf cisco IS-IS interface URL
+func NetconfCiscoIsisInterfaceURL(device string, instance string, interfaceName string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-clns-isis-cfg:isis/instances").
+		Entry("instance", instance).
+		Child("interfaces").
+		Entry("interface", interfaceName).String()
+}
+
+// NetconfCiscoOspfInterfaceURL returns netconf cisco OSPF interface URL, the area is either an
+// integer area-area-id or a dotted area-address
+func NetconfCiscoOspfInterfaceURL(device string, process string, area string, interfaceName string) string {
+	areaList := "area-address"
+	if _, err := strconv.Atoi(area); err == nil {
+		areaList = "area-area-id"
+	}
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-ipv4-ospf-cfg:ospf/processes").
+		Entry("process", process).
+		Child("default-vrf/area-addresses").
+		Entry(areaList, area).
+		Child("name-scopes").
+		Entry("name-scope", interfaceName).String()
+}
+
+// NetconfCiscoIsisInterfacePayload forms a json payload for cisco IS-IS interface
+func NetconfCiscoIsisInterfacePayload(intf CiscoIsisInterface) (bytes.Buffer, error) {
+	payloadBody := CiscoIsisInterfacePayload{
+		Node: []CiscoIsisInterface{intf},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoIsisInterfacePayload parses json payload for cisco IS-IS interface to a struct
+func ParseNetconfCiscoIsisInterfacePayload(bodyBytes []byte) (CiscoIsisInterface, error) {
+	item := &CiscoIsisInterfacePayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoIsisInterface{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var intf CiscoIsisInterface = item.Node[0]
+	return intf, nil
+}
+
+// NetconfCiscoOspfInterfacePayload forms a json payload for cisco OSPF interface
+func NetconfCiscoOspfInterfacePayload(intf CiscoOspfInterface) (bytes.Buffer, error) {
+	payloadBody := CiscoOspfInterfacePayload{
+		Node: []CiscoOspfInterface{intf},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoOspfInterfacePayload parses json payload for cisco OSPF interface to a struct
+func ParseNetconfCiscoOspfInterfacePayload(bodyBytes []byte) (CiscoOspfInterface, error) {
+	item := &CiscoOspfInterfacePayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoOspfInterface{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var intf CiscoOspfInterface = item.Node[0]
+	return intf, nil
+}
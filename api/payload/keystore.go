@@ -0,0 +1,92 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+)
+
+// KeystoreEntryPayload struct
+type KeystoreEntryPayload struct {
+	Node []KeystoreEntry `json:"key-credential"`
+}
+
+// KeystoreEntry struct represents a netconf-keystore key-credential
+type KeystoreEntry struct {
+	KeyID      string `json:"key-id"`
+	PrivateKey string `json:"private-key,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// KeystoreAddInput struct is the input of the add-keystore-entry RPC
+type KeystoreAddInput struct {
+	Input KeystoreEntryPayload `json:"input"`
+}
+
+// KeystoreRemoveInput struct is the input of the remove-keystore-entry RPC
+type KeystoreRemoveInput struct {
+	Input struct {
+		KeyID []string `json:"key-id"`
+	} `json:"input"`
+}
+
+// NetconfKeystoreEntryURL returns netconf-keystore key-credential URL
+func NetconfKeystoreEntryURL(keyID string) string {
+	return ConfigPath().Child("netconf-keystore:keystore").Entry("key-credential", keyID).String()
+}
+
+// NetconfKeystoreAddURL returns the add-keystore-entry RPC URL
+func NetconfKeystoreAddURL() string {
+	return OperationsPath().Child("netconf-keystore:add-keystore-entry").String()
+}
+
+// NetconfKeystoreRemoveURL returns the remove-keystore-entry RPC URL
+func NetconfKeystoreRemoveURL() string {
+	return OperationsPath().Child("netconf-keystore:remove-keystore-entry").String()
+}
+
+// NetconfKeystoreAddPayload forms a json add-keystore-entry RPC input
+func NetconfKeystoreAddPayload(entry KeystoreEntry) (bytes.Buffer, error) {
+	payloadBody := KeystoreAddInput{
+		Input: KeystoreEntryPayload{
+			Node: []KeystoreEntry{entry},
+		},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// NetconfKeystoreRemovePayload forms a json remove-keystore-entry RPC input
+func NetconfKeystoreRemovePayload(keyID string) (bytes.Buffer, error) {
+	payloadBody := KeystoreRemoveInput{}
+	payloadBody.Input.KeyID = []string{keyID}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfKeystoreEntryPayload parses json payload for a keystore key-credential to a struct
+func ParseNetconfKeystoreEntryPayload(bodyBytes []byte) (KeystoreEntry, error) {
+	item := &KeystoreEntryPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return KeystoreEntry{}, err
+	}
+
+	var entry KeystoreEntry = item.Node[0]
+
+	// Keep the private key out of the logs
+	log.Printf("[DEBUG] Parsed Body: key-id %s", entry.KeyID)
+
+	return entry, nil
+}
@@ -0,0 +1,97 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Path is a YANG instance identifier on the controller. Nodes and list keys are kept apart and
+// only encoded when the path is turned into a url, so key values can hold any character
+type Path struct {
+	datastore string
+	nodes     []pathNode
+}
+
+// pathNode is a container, or a list entry when it has keys
+type pathNode struct {
+	name string
+	keys []string
+}
+
+// ConfigPath returns the root of the configuration datastore
+func ConfigPath() Path {
+	return Path{datastore: "config"}
+}
+
+// OperationalPath returns the root of the operational datastore
+func OperationalPath() Path {
+	return Path{datastore: "operational"}
+}
+
+// OperationsPath returns the root of the RPCs
+func OperationsPath() Path {
+	return Path{datastore: "operations"}
+}
+
+// Child appends containers to the path, nested containers can be given as a/b/c. The first
+// node of a module, and nodes augmented into another module, are qualified as module:name
+func (p Path) Child(names string) Path {
+	for _, name := range strings.Split(names, "/") {
+		p = p.append(pathNode{name: name})
+	}
+	return p
+}
+
+// Entry appends the entry of a list with its key values in the order the list defines them
+func (p Path) Entry(list string, keys ...interface{}) Path {
+	node := pathNode{name: list}
+	for _, key := range keys {
+		node.keys = append(node.keys, fmt.Sprint(key))
+	}
+	return p.append(node)
+}
+
+// NetconfNode appends the node of a netconf device in the netconf topology
+func (p Path) NetconfNode(node string) Path {
+	return p.Child("network-topology:network-topology").Entry("topology", "topology-netconf").Entry("node", node)
+}
+
+// Mount appends the mount point of a netconf device, the device's own models follow it
+func (p Path) Mount(node string) Path {
+	return p.NetconfNode(node).Child("yang-ext:mount")
+}
+
+// append returns a copy of the path with the node at the end, so paths can share a prefix
+func (p Path) append(node pathNode) Path {
+	nodes := make([]pathNode, len(p.nodes), len(p.nodes)+1)
+	copy(nodes, p.nodes)
+	p.nodes = append(nodes, node)
+	return p
+}
+
+// String returns the draft02 url of the controller, every key value is a segment of its own
+func (p Path) String() string {
+	segments := []string{"restconf", p.datastore}
+	for _, node := range p.nodes {
+		segments = append(segments, node.name)
+		for _, key := range node.keys {
+			segments = append(segments, escapeKey(key))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// escapeKey percent-encodes a key value the way RFC 8040 encodes list keys, only the unreserved
+// characters of RFC 3986 are kept so / , : and spaces in values can't be mistaken for the path's syntax
+func escapeKey(value string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,126 @@
+package payload
+
+import "testing"
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    Path
+		draft02 string
+	}{
+		{
+			name:    "containers",
+			path:    ConfigPath().Child("Cisco-IOS-XR-ifmgr-cfg:interface-configurations"),
+			draft02: "restconf/config/Cisco-IOS-XR-ifmgr-cfg:interface-configurations",
+		},
+		{
+			name:    "nested containers",
+			path:    ConfigPath().Child("Cisco-IOS-XR-l2vpn-cfg:l2vpn/database"),
+			draft02: "restconf/config/Cisco-IOS-XR-l2vpn-cfg:l2vpn/database",
+		},
+		{
+			name:    "name with slashes",
+			path:    ConfigPath().Entry("interface-configuration", "act", "GigabitEthernet0/0/0/1"),
+			draft02: "restconf/config/interface-configuration/act/GigabitEthernet0%2F0%2F0%2F1",
+		},
+		{
+			name:    "name with spaces",
+			path:    ConfigPath().Entry("class-map", "qos", "gold traffic"),
+			draft02: "restconf/config/class-map/qos/gold%20traffic",
+		},
+		{
+			name:    "name with commas",
+			path:    ConfigPath().Entry("policy-map", "qos", "in,out"),
+			draft02: "restconf/config/policy-map/qos/in%2Cout",
+		},
+		{
+			name:    "name with colons and percents",
+			path:    ConfigPath().Entry("vrf", "a:b%c"),
+			draft02: "restconf/config/vrf/a%3Ab%25c",
+		},
+		{
+			name:    "unreserved characters",
+			path:    ConfigPath().Entry("vrf", "a-b.c_d~e"),
+			draft02: "restconf/config/vrf/a-b.c_d~e",
+		},
+		{
+			name:    "multi-key entry",
+			path:    ConfigPath().Entry("neighbor", "default", 65000, "2001:db8::1"),
+			draft02: "restconf/config/neighbor/default/65000/2001%3Adb8%3A%3A1",
+		},
+		{
+			name:    "entry with an empty key",
+			path:    ConfigPath().Entry("entry", "", "b"),
+			draft02: "restconf/config/entry//b",
+		},
+		{
+			name:    "netconf node",
+			path:    ConfigPath().NetconfNode("r1"),
+			draft02: "restconf/config/network-topology:network-topology/topology/topology-netconf/node/r1",
+		},
+		{
+			name:    "operational netconf node",
+			path:    OperationalPath().NetconfNode("r 1"),
+			draft02: "restconf/operational/network-topology:network-topology/topology/topology-netconf/node/r%201",
+		},
+		{
+			name: "mount composition",
+			path: ConfigPath().Mount("r1").
+				Child("Cisco-IOS-XR-ifmgr-cfg:interface-configurations").
+				Entry("interface-configuration", "pre", "Bundle-Ether1.100"),
+			draft02: "restconf/config/network-topology:network-topology/topology/topology-netconf/node/r1/yang-ext:mount/" +
+				"Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/pre/Bundle-Ether1.100",
+		},
+		{
+			name:    "mount of a device with a slash",
+			path:    ConfigPath().Mount("site/r1").Child("Cisco-IOS-XR-shellutil-cfg:host-names"),
+			draft02: "restconf/config/network-topology:network-topology/topology/topology-netconf/node/site%2Fr1/yang-ext:mount/Cisco-IOS-XR-shellutil-cfg:host-names",
+		},
+		{
+			name:    "operations",
+			path:    OperationsPath().Child("odl-netconf-callhome-server:netconf-callhome-server"),
+			draft02: "restconf/operations/odl-netconf-callhome-server:netconf-callhome-server",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if url := test.path.String(); url != test.draft02 {
+				t.Errorf("expected draft02 url\n%s\ngot\n%s", test.draft02, url)
+			}
+		})
+	}
+}
+
+// Paths that share a prefix don't share their nodes
+func TestPathSharedPrefix(t *testing.T) {
+	mount := ConfigPath().Mount("r1")
+	first := mount.Child("a")
+	second := mount.Child("b")
+	if first.String() == second.String() {
+		t.Errorf("expected paths from the same prefix to differ, got %s", first)
+	}
+	if mount.String() != "restconf/config/network-topology:network-topology/topology/topology-netconf/node/r1/yang-ext:mount" {
+		t.Errorf("expected the prefix to be left unchanged, got %s", mount)
+	}
+}
+
+func TestSplitMountURL(t *testing.T) {
+	url := ConfigPath().Mount("r1").Child("Cisco-IOS-XR-ifmgr-cfg:interface-configurations").
+		Entry("interface-configuration", "pre", "GigabitEthernet0/0/0/1").String()
+	mountURL, target, ok := SplitMountURL(url)
+	if !ok {
+		t.Fatalf("expected %s to be below a mount point", url)
+	}
+	if mountURL != ConfigPath().Mount("r1").String() {
+		t.Errorf("expected the mount url, got %s", mountURL)
+	}
+	if target != "/Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/pre/GigabitEthernet0%2F0%2F0%2F1" {
+		t.Errorf("expected the target relative to the mount, got %s", target)
+	}
+
+	for _, url := range []string{ConfigPath().NetconfNode("r1").String(), OperationalPath().Mount("r1").Child("a").String()} {
+		if _, _, ok := SplitMountURL(url); ok {
+			t.Errorf("expected %s not to be split", url)
+		}
+	}
+}
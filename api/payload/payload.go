@@ -3,26 +3,28 @@ package payload
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"log"
-	"net/url"
 )
 
-// Netconf struct represents a Netconf Device Details
-type Netconf struct {
-	Name      string `json:"node-id"`
-	IPAddress string `json:"netconf-node-topology:host"`
-	Port      int    `json:"netconf-node-topology:port"`
-	Username  string `json:"netconf-node-topology:username"`
-	Password  string `json:"netconf-node-topology:password"`
-}
-
 // NetconfOperational struct represents a Netconf Opertaional Device Details
 type NetconfOperational struct {
 	Name      string `json:"node-id"`
 	IPAddress string `json:"netconf-node-topology:host"`
 	Port      int    `json:"netconf-node-topology:port"`
 	Status    string `json:"netconf-node-topology:connection-status"`
+
+	AvailableCapabilities *AvailableCapabilities `json:"netconf-node-topology:available-capabilities,omitempty"`
+}
+
+// AvailableCapabilities struct lists the capabilities of a connected device
+type AvailableCapabilities struct {
+	AvailableCapability []AvailableCapability `json:"available-capability"`
+}
+
+// AvailableCapability struct, YANG modules are in (namespace?revision=date)module format
+type AvailableCapability struct {
+	Capability       string `json:"capability"`
+	CapabilityOrigin string `json:"capability-origin,omitempty"`
 }
 
 // NetconfPayload struct
@@ -37,12 +39,12 @@ type NetconfPayloadOperational struct {
 
 // NetconfMountURL returns netconf mount URL - needs check for empty name?
 func NetconfMountURL(name string) string {
-	return fmt.Sprintf("restconf/config/network-topology:network-topology/topology/topology-netconf/node/%s", name)
+	return ConfigPath().NetconfNode(name).String()
 }
 
 // NetconfMountURLOperational returns netconf mount Operational URL - needs check for empty name?
 func NetconfMountURLOperational(name string) string {
-	return fmt.Sprintf("restconf/operational/network-topology:network-topology/topology/topology-netconf/node/%s", name)
+	return OperationalPath().NetconfNode(name).String()
 }
 
 // NetconfMountPayload forms a json payload for Netconf Mount
@@ -62,54 +64,56 @@ func NetconfMountPayload(device Netconf) (bytes.Buffer, error) {
 // ParseNetconfMountPayload parses the json netconf mount payload to a struct
 func ParseNetconfMountPayload(bodyBytes []byte) (Netconf, error) {
 	item := &NetconfPayload{}
-	err := json.Unmarshal(bodyBytes, item)
+	err := Unmarshal(bodyBytes, item)
 	if err != nil {
 		log.Print("[Error]: ", err)
 		return Netconf{}, err
 	}
 
-	log.Printf("[DEBUG] Parsed Body: ", item)
-
 	var device Netconf = item.Node[0]
+
+	// Keep the passwords out of the logs
+	log.Printf("[DEBUG] Parsed Body: node-id %s", device.NodeId)
+
 	return device, nil
 }
 
 // ParseNetconfOperationalMountPayload parses the json netconf mount payload to a struct
 func ParseNetconfOperationalMountPayload(bodyBytes []byte) (NetconfOperational, error) {
 	item := &NetconfPayloadOperational{}
-	err := json.Unmarshal(bodyBytes, item)
+	err := Unmarshal(bodyBytes, item)
 	if err != nil {
 		log.Print("[Error]: ", err)
 		return NetconfOperational{}, err
 	}
 
-	log.Printf("[DEBUG] Parsed Body: ", item)
+	log.Printf("[DEBUG] Parsed Body: %v", item)
 
 	var device NetconfOperational = item.Node[0]
 	return device, nil
 }
 
-// CiscoInterface struct
-type CiscoInterface struct {
-	Active      string `json:"active"`
-	Name        string `json:"interface-name"`
-	Description string `json:"description"`
+// InterfaceConfigurationPayload struct
+type InterfaceConfigurationPayload struct {
+	Node []InterfaceConfiguration `json:"interface-configuration"`
 }
 
-// CiscoInterfacePayload struct
-type CiscoInterfacePayload struct {
-	Node []CiscoInterface `json:"interface-configuration"`
+// interfaceConfigurationPath returns the path of the pre-configuration of an interface
+func interfaceConfigurationPath(device string, interfaceName string) Path {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-ifmgr-cfg:interface-configurations").
+		Entry("interface-configuration", "pre", interfaceName)
 }
 
 // NetconfCiscoInterfaceURL returns netconf cisco interface URL
 func NetconfCiscoInterfaceURL(device string, interfaceName string) string {
-	return fmt.Sprintf("restconf/config/network-topology:network-topology/topology/topology-netconf/node/%s/yang-ext:mount/Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/pre/%s", device, url.QueryEscape(interfaceName))
+	return interfaceConfigurationPath(device, interfaceName).String()
 }
 
 // NetconfCiscoInterfacePayload forms a json payload for cisco interface
-func NetconfCiscoInterfacePayload(device CiscoInterface) (bytes.Buffer, error) {
-	payloadBody := CiscoInterfacePayload{ // Make into seperate function
-		Node: []CiscoInterface{device},
+func NetconfCiscoInterfacePayload(device InterfaceConfiguration) (bytes.Buffer, error) {
+	payloadBody := InterfaceConfigurationPayload{ // Make into seperate function
+		Node: []InterfaceConfiguration{device},
 	}
 
 	buf := bytes.Buffer{}
@@ -121,62 +125,29 @@ func NetconfCiscoInterfacePayload(device CiscoInterface) (bytes.Buffer, error) {
 }
 
 // ParseNetconfCiscoInterfacePayload parses json payload for cisco interface to a struct
-func ParseNetconfCiscoInterfacePayload(bodyBytes []byte) (CiscoInterface, error) {
-	item := &CiscoInterfacePayload{}
-	err := json.Unmarshal(bodyBytes, item)
+func ParseNetconfCiscoInterfacePayload(bodyBytes []byte) (InterfaceConfiguration, error) {
+	item := &InterfaceConfigurationPayload{}
+	err := Unmarshal(bodyBytes, item)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return CiscoInterface{}, err
+		return InterfaceConfiguration{}, err
 	}
 
-	log.Printf("[DEBUG] Parsed Body: ", item)
+	log.Printf("[DEBUG] Parsed Body: %v", item)
 
-	var device CiscoInterface = item.Node[0]
+	var device InterfaceConfiguration = item.Node[0]
 	return device, nil
 }
 
-type CiscoVlanPayload struct {
-	Node []CiscoVlan `json:"interface-configuration"`
-}
-type Mtu struct {
-	Owner string `json:"owner"`
-	Mtu   int    `json:"mtu"`
-}
-type Mtus struct {
-	Mtu []Mtu `json:"mtu"`
-}
-type Encapsulation struct {
-	OuterTagType string `json:"outer-tag-type"`
-}
-type Rewrite struct {
-	InnerTagType  string `json:"inner-tag-type"`
-	InnerTagValue int    `json:"inner-tag-value"`
-	OuterTagType  string `json:"outer-tag-type"`
-	RewriteType   string `json:"rewrite-type"`
-	OuterTagValue int    `json:"outer-tag-value"`
-}
-type CiscoIOSXRL2EthInfraCfgEthernetService struct {
-	Encapsulation Encapsulation `json:"encapsulation"`
-	Rewrite       Rewrite       `json:"rewrite"`
-}
-type CiscoVlan struct {
-	Active                                 string                                 `json:"active"`
-	InterfaceName                          string                                 `json:"interface-name"`
-	Description                            string                                 `json:"description"`
-	Mtus                                   Mtus                                   `json:"mtus"`
-	InterfaceModeNonPhysical               string                                 `json:"interface-mode-non-physical"`
-	CiscoIOSXRL2EthInfraCfgEthernetService CiscoIOSXRL2EthInfraCfgEthernetService `json:"Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service"`
-}
-
 // NetconfCiscoVlanURL returns netconf cisco interface URL
 func NetconfCiscoVlanURL(device string, interfaceName string) string {
-	return fmt.Sprintf("restconf/config/network-topology:network-topology/topology/topology-netconf/node/%s/yang-ext:mount/Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/pre/%s", device, url.QueryEscape(interfaceName))
+	return interfaceConfigurationPath(device, interfaceName).String()
 }
 
 // NetconfCiscoVlanPayload forms a json payload for cisco interface
-func NetconfCiscoVlanPayload(device CiscoVlan) (bytes.Buffer, error) {
-	payloadBody := CiscoVlanPayload{ // Make into seperate function
-		Node: []CiscoVlan{device},
+func NetconfCiscoVlanPayload(device InterfaceConfiguration) (bytes.Buffer, error) {
+	payloadBody := InterfaceConfigurationPayload{ // Make into seperate function
+		Node: []InterfaceConfiguration{device},
 	}
 
 	buf := bytes.Buffer{}
@@ -188,43 +159,36 @@ func NetconfCiscoVlanPayload(device CiscoVlan) (bytes.Buffer, error) {
 }
 
 // ParseNetconfCiscoVlanPayload parses json payload for cisco interface to a struct
-func ParseNetconfCiscoVlanPayload(bodyBytes []byte) (CiscoVlan, error) {
-	item := &CiscoVlanPayload{}
-	err := json.Unmarshal(bodyBytes, item)
+func ParseNetconfCiscoVlanPayload(bodyBytes []byte) (InterfaceConfiguration, error) {
+	item := &InterfaceConfigurationPayload{}
+	err := Unmarshal(bodyBytes, item)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return CiscoVlan{}, err
+		return InterfaceConfiguration{}, err
 	}
 
-	log.Printf("[DEBUG] Parsed Body: ", item)
+	log.Printf("[DEBUG] Parsed Body: %v", item)
 
-	var device CiscoVlan = item.Node[0]
+	var device InterfaceConfiguration = item.Node[0]
 	return device, nil
 }
 
+// CiscoL2VPNPayload struct
 type CiscoL2VPNPayload struct {
-	Node []CiscoL2VPN `json:"vlan-aware-flexible-xconnect-service"`
-}
-type VlanAwareFxcAttachmentCircuit struct {
-	Name string `json:"name"`
-}
-type VlanAwareFxcAttachmentCircuits struct {
-	VlanAwareFxcAttachmentCircuit []VlanAwareFxcAttachmentCircuit `json:"vlan-aware-fxc-attachment-circuit"`
-}
-type CiscoL2VPN struct {
-	Eviid                          int                            `json:"eviid"`
-	VlanAwareFxcAttachmentCircuits VlanAwareFxcAttachmentCircuits `json:"vlan-aware-fxc-attachment-circuits"`
+	Node []VlanAwareFlexibleXconnectService `json:"vlan-aware-flexible-xconnect-service"`
 }
 
 // NetconfCiscoL2VPNURL returns netconf cisco interface URL
 func NetconfCiscoL2VPNURL(device string, eviid int) string {
-	return fmt.Sprintf("restconf/config/network-topology:network-topology/topology/topology-netconf/node/%s/yang-ext:mount/Cisco-IOS-XR-l2vpn-cfg:l2vpn/database/flexible-xconnect-service-table/vlan-aware-flexible-xconnect-services/vlan-aware-flexible-xconnect-service/%d", device, eviid)
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-l2vpn-cfg:l2vpn/database/flexible-xconnect-service-table/vlan-aware-flexible-xconnect-services").
+		Entry("vlan-aware-flexible-xconnect-service", eviid).String()
 }
 
 // NetconfCiscoL2VPNPayload forms a json payload for cisco interface
-func NetconfCiscoL2VPNPayload(device CiscoL2VPN) (bytes.Buffer, error) {
+func NetconfCiscoL2VPNPayload(device VlanAwareFlexibleXconnectService) (bytes.Buffer, error) {
 	payloadBody := CiscoL2VPNPayload{ // Make into seperate function
-		Node: []CiscoL2VPN{device},
+		Node: []VlanAwareFlexibleXconnectService{device},
 	}
 
 	buf := bytes.Buffer{}
@@ -236,16 +200,29 @@ func NetconfCiscoL2VPNPayload(device CiscoL2VPN) (bytes.Buffer, error) {
 }
 
 // ParseNetconfCiscoL2VPNPayload parses json payload for cisco interface to a struct
-func ParseNetconfCiscoL2VPNPayload(bodyBytes []byte) (CiscoL2VPN, error) {
+func ParseNetconfCiscoL2VPNPayload(bodyBytes []byte) (VlanAwareFlexibleXconnectService, error) {
 	item := &CiscoL2VPNPayload{}
-	err := json.Unmarshal(bodyBytes, item)
+	err := Unmarshal(bodyBytes, item)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return CiscoL2VPN{}, err
+		return VlanAwareFlexibleXconnectService{}, err
 	}
 
-	log.Printf("[DEBUG] Parsed Body: ", item)
+	log.Printf("[DEBUG] Parsed Body: %v", item)
 
-	var device CiscoL2VPN = item.Node[0]
+	var device VlanAwareFlexibleXconnectService = item.Node[0]
 	return device, nil
 }
+
+// Empty represents a YANG leaf of type empty, which is encoded as [null] in json
+type Empty struct{}
+
+// MarshalJSON encodes an empty leaf
+func (Empty) MarshalJSON() ([]byte, error) {
+	return []byte("[null]"), nil
+}
+
+// UnmarshalJSON accepts any value for an empty leaf, its presence is all that matters
+func (*Empty) UnmarshalJSON([]byte) error {
+	return nil
+}
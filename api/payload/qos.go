@@ -0,0 +1,190 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+)
+
+// CiscoClassMapPayload struct
+type CiscoClassMapPayload struct {
+	Node []CiscoClassMap `json:"class-map"`
+}
+
+// CiscoClassMap struct represents a Cisco-IOS-XR-infra-policymgr-cfg qos class-map
+type CiscoClassMap struct {
+	Type                 string         `json:"type"`
+	Name                 string         `json:"name"`
+	ClassMapModeMatchAny *Empty         `json:"class-map-mode-match-any,omitempty"`
+	ClassMapModeMatchAll *Empty         `json:"class-map-mode-match-all,omitempty"`
+	Description          string         `json:"description,omitempty"`
+	Match                *ClassMapMatch `json:"match,omitempty"`
+}
+
+// ClassMapMatch struct
+type ClassMapMatch struct {
+	Dscp       []string `json:"dscp,omitempty"`
+	Precedence []string `json:"precedence,omitempty"`
+	Cos        []int    `json:"cos,omitempty"`
+}
+
+// CiscoPolicyMapPayload struct
+type CiscoPolicyMapPayload struct {
+	Node []CiscoPolicyMap `json:"policy-map"`
+}
+
+// CiscoPolicyMap struct represents a Cisco-IOS-XR-infra-policymgr-cfg qos policy-map
+type CiscoPolicyMap struct {
+	Type          string          `json:"type"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description,omitempty"`
+	PolicyMapRule []PolicyMapRule `json:"policy-map-rule,omitempty"`
+}
+
+// PolicyMapRule struct is the actions applied to a single class
+type PolicyMapRule struct {
+	ClassName string        `json:"class-name"`
+	ClassType string        `json:"class-type"`
+	Police    *PolicyPolice `json:"police,omitempty"`
+	Shape     *PolicyShape  `json:"shape,omitempty"`
+	Set       *PolicySet    `json:"set,omitempty"`
+}
+
+// PolicyRate struct
+type PolicyRate struct {
+	Value int    `json:"value"`
+	Units string `json:"units"`
+}
+
+// PolicyPolice struct
+type PolicyPolice struct {
+	Rate          PolicyRate    `json:"rate"`
+	Burst         *PolicyRate   `json:"burst,omitempty"`
+	ConformAction *PolicyAction `json:"conform-action,omitempty"`
+	ExceedAction  *PolicyAction `json:"exceed-action,omitempty"`
+}
+
+// PolicyAction struct
+type PolicyAction struct {
+	Transmit *Empty `json:"transmit,omitempty"`
+	Drop     *Empty `json:"drop,omitempty"`
+}
+
+// PolicyShape struct
+type PolicyShape struct {
+	Rate PolicyRate `json:"rate"`
+}
+
+// PolicySet struct
+type PolicySet struct {
+	Dscp string `json:"dscp,omitempty"`
+	Cos  int    `json:"cos,omitempty"`
+}
+
+// NewInterfaceQos returns the service policies of an interface by policy-map name, or nil when
+// neither is set
+func NewInterfaceQos(input string, output string) *InterfaceConfigurationQos {
+	if input == "" && output == "" {
+		return nil
+	}
+	qos := &InterfaceConfigurationQos{}
+	if input != "" {
+		qos.Input = &InterfaceConfigurationQosInput{
+			ServicePolicy: []InterfaceConfigurationQosInputServicePolicy{{ServicePolicyName: input}},
+		}
+	}
+	if output != "" {
+		qos.Output = &InterfaceConfigurationQosOutput{
+			ServicePolicy: []InterfaceConfigurationQosOutputServicePolicy{{ServicePolicyName: output}},
+		}
+	}
+	return qos
+}
+
+// InputPolicy returns the first ingress service policy name, or an empty string when none is attached
+func (q *InterfaceConfigurationQos) InputPolicy() string {
+	if q == nil || q.Input == nil || len(q.Input.ServicePolicy) == 0 {
+		return ""
+	}
+	return q.Input.ServicePolicy[0].ServicePolicyName
+}
+
+// OutputPolicy returns the first egress service policy name, or an empty string when none is attached
+func (q *InterfaceConfigurationQos) OutputPolicy() string {
+	if q == nil || q.Output == nil || len(q.Output.ServicePolicy) == 0 {
+		return ""
+	}
+	return q.Output.ServicePolicy[0].ServicePolicyName
+}
+
+// NetconfCiscoClassMapURL returns netconf cisco qos class-map URL
+func NetconfCiscoClassMapURL(device string, name string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-infra-policymgr-cfg:policy-manager/class-maps").
+		Entry("class-map", "qos", name).String()
+}
+
+// NetconfCiscoPolicyMapURL returns netconf cisco qos policy-map URL
+func NetconfCiscoPolicyMapURL(device string, name string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-infra-policymgr-cfg:policy-manager/policy-maps").
+		Entry("policy-map", "qos", name).String()
+}
+
+// NetconfCiscoClassMapPayload forms a json payload for cisco class-map
+func NetconfCiscoClassMapPayload(classMap CiscoClassMap) (bytes.Buffer, error) {
+	payloadBody := CiscoClassMapPayload{
+		Node: []CiscoClassMap{classMap},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoClassMapPayload parses json payload for cisco class-map to a struct
+func ParseNetconfCiscoClassMapPayload(bodyBytes []byte) (CiscoClassMap, error) {
+	item := &CiscoClassMapPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoClassMap{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var classMap CiscoClassMap = item.Node[0]
+	return classMap, nil
+}
+
+// NetconfCiscoPolicyMapPayload forms a json payload for cisco policy-map
+func NetconfCiscoPolicyMapPayload(policyMap CiscoPolicyMap) (bytes.Buffer, error) {
+	payloadBody := CiscoPolicyMapPayload{
+		Node: []CiscoPolicyMap{policyMap},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoPolicyMapPayload parses json payload for cisco policy-map to a struct
+func ParseNetconfCiscoPolicyMapPayload(bodyBytes []byte) (CiscoPolicyMap, error) {
+	item := &CiscoPolicyMapPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoPolicyMap{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var policyMap CiscoPolicyMap = item.Node[0]
+	return policyMap, nil
+}
@@ -0,0 +1,195 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// CiscoRoutePolicyPayload struct
+type CiscoRoutePolicyPayload struct {
+	Node []CiscoRoutePolicy `json:"route-policy"`
+}
+
+// CiscoRoutePolicy struct represents a Cisco-IOS-XR-policy-repository-cfg route-policy
+type CiscoRoutePolicy struct {
+	RoutePolicyName string `json:"route-policy-name"`
+	RplRoutePolicy  string `json:"rpl-route-policy"`
+}
+
+// CiscoPrefixSetPayload struct
+type CiscoPrefixSetPayload struct {
+	Node []CiscoPrefixSet `json:"prefix-set"`
+}
+
+// CiscoPrefixSet struct represents a Cisco-IOS-XR-policy-repository-cfg prefix-set
+type CiscoPrefixSet struct {
+	SetName      string `json:"set-name"`
+	RplPrefixSet string `json:"rpl-prefix-set"`
+}
+
+// NormalizeRPL normalizes RPL text the way the device reformats it, line endings are converted
+// to \n, runs of whitespace are collapsed to a single space and blank lines are dropped
+func NormalizeRPL(text string) string {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	text = strings.Replace(text, "\r", "\n", -1)
+
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NormalizePrefixSet normalizes a prefix-set body like NormalizeRPL and puts each comma separated
+// entry on its own line, in the order they are given. Comments stay on lines of their own
+func NormalizePrefixSet(text string) string {
+	type line struct {
+		text    string
+		comment bool
+	}
+	lines := []line{}
+	lastEntry := -1
+	for _, text := range strings.Split(NormalizeRPL(text), "\n") {
+		if strings.HasPrefix(text, "#") {
+			lines = append(lines, line{text: text, comment: true})
+			continue
+		}
+		for _, entry := range strings.Split(text, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry != "" {
+				lastEntry = len(lines)
+				lines = append(lines, line{text: entry})
+			}
+		}
+	}
+
+	normalized := make([]string, len(lines))
+	for i, line := range lines {
+		normalized[i] = line.text
+		if !line.comment && i < lastEntry {
+			normalized[i] += ","
+		}
+	}
+	return strings.Join(normalized, "\n")
+}
+
+// rplWrap wraps an RPL body in its header and footer statements
+func rplWrap(header string, footer string, body string) string {
+	return fmt.Sprintf("%s\n%s\n%s\n", header, strings.TrimRight(strings.Replace(body, "\r\n", "\n", -1), "\n"), footer)
+}
+
+// rplUnwrap returns the normalized RPL body without its header and footer statements
+func rplUnwrap(header string, footer string, text string) string {
+	lines := strings.Split(NormalizeRPL(text), "\n")
+	if len(lines) > 0 && lines[0] == header {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == footer {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewCiscoRoutePolicy returns a route-policy with the RPL body wrapped in its header and footer
+func NewCiscoRoutePolicy(name string, body string) CiscoRoutePolicy {
+	return CiscoRoutePolicy{
+		RoutePolicyName: name,
+		RplRoutePolicy:  rplWrap("route-policy "+name, "end-policy", body),
+	}
+}
+
+// Body returns the normalized RPL body of the route-policy
+func (p CiscoRoutePolicy) Body() string {
+	return rplUnwrap("route-policy "+p.RoutePolicyName, "end-policy", p.RplRoutePolicy)
+}
+
+// NewCiscoPrefixSet returns a prefix-set with the RPL body wrapped in its header and footer
+func NewCiscoPrefixSet(name string, body string) CiscoPrefixSet {
+	return CiscoPrefixSet{
+		SetName:      name,
+		RplPrefixSet: rplWrap("prefix-set "+name, "end-set", body),
+	}
+}
+
+// Body returns the normalized RPL body of the prefix-set
+func (s CiscoPrefixSet) Body() string {
+	return NormalizePrefixSet(rplUnwrap("prefix-set "+s.SetName, "end-set", s.RplPrefixSet))
+}
+
+// NetconfCiscoRoutePolicyURL returns netconf cisco route-policy URL
+func NetconfCiscoRoutePolicyURL(device string, name string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-policy-repository-cfg:routing-policy/route-policies").
+		Entry("route-policy", name).String()
+}
+
+// NetconfCiscoPrefixSetURL returns netconf cisco prefix-set URL
+func NetconfCiscoPrefixSetURL(device string, name string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-policy-repository-cfg:routing-policy/sets/prefix-sets").
+		Entry("prefix-set", name).String()
+}
+
+// NetconfCiscoRoutePolicyPayload forms a json payload for cisco route-policy
+func NetconfCiscoRoutePolicyPayload(policy CiscoRoutePolicy) (bytes.Buffer, error) {
+	payloadBody := CiscoRoutePolicyPayload{
+		Node: []CiscoRoutePolicy{policy},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoRoutePolicyPayload parses json payload for cisco route-policy to a struct
+func ParseNetconfCiscoRoutePolicyPayload(bodyBytes []byte) (CiscoRoutePolicy, error) {
+	item := &CiscoRoutePolicyPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoRoutePolicy{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var policy CiscoRoutePolicy = item.Node[0]
+	return policy, nil
+}
+
+// NetconfCiscoPrefixSetPayload forms a json payload for cisco prefix-set
+func NetconfCiscoPrefixSetPayload(set CiscoPrefixSet) (bytes.Buffer, error) {
+	payloadBody := CiscoPrefixSetPayload{
+		Node: []CiscoPrefixSet{set},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoPrefixSetPayload parses json payload for cisco prefix-set to a struct
+func ParseNetconfCiscoPrefixSetPayload(bodyBytes []byte) (CiscoPrefixSet, error) {
+	item := &CiscoPrefixSetPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoPrefixSet{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var set CiscoPrefixSet = item.Node[0]
+	return set, nil
+}
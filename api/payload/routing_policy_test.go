@@ -0,0 +1,129 @@
+package payload
+
+import "testing"
+
+func TestNormalizeRPL(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "line endings",
+			text:     "if destination in customers then\r\n  pass\r\nendif\r",
+			expected: "if destination in customers then\npass\nendif",
+		},
+		{
+			name:     "indentation",
+			text:     "if destination in customers then\n    if med eq 10 then\n\t\tpass\n    endif\nendif\n",
+			expected: "if destination in customers then\nif med eq 10 then\npass\nendif\nendif",
+		},
+		{
+			name:     "runs of whitespace",
+			text:     "set   local-preference\t 200  ",
+			expected: "set local-preference 200",
+		},
+		{
+			name:     "blank lines",
+			text:     "\n\npass\n   \n\ndone\n\n",
+			expected: "pass\ndone",
+		},
+		{
+			name:     "comments",
+			text:     "  # prefer   customer routes\n  set local-preference 200",
+			expected: "# prefer customer routes\nset local-preference 200",
+		},
+		{
+			name:     "statement order",
+			text:     "set med 10\nset local-preference 200",
+			expected: "set med 10\nset local-preference 200",
+		},
+		{
+			name:     "empty",
+			text:     " \r\n\t",
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if normalized := NormalizeRPL(test.text); normalized != test.expected {
+				t.Errorf("expected\n%q\ngot\n%q", test.expected, normalized)
+			}
+		})
+	}
+}
+
+func TestNormalizePrefixSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "entry per line",
+			text:     "  10.0.0.0/8 le 24,\r\n  192.168.0.0/16\r\n",
+			expected: "10.0.0.0/8 le 24,\n192.168.0.0/16",
+		},
+		{
+			name:     "entries on one line",
+			text:     "10.0.0.0/8 le 24,192.168.0.0/16 ,  172.16.0.0/12",
+			expected: "10.0.0.0/8 le 24,\n192.168.0.0/16,\n172.16.0.0/12",
+		},
+		{
+			name:     "entry order is kept",
+			text:     "192.168.0.0/16,\n10.0.0.0/8",
+			expected: "192.168.0.0/16,\n10.0.0.0/8",
+		},
+		{
+			name:     "runs of whitespace",
+			text:     "10.0.0.0/8    ge 16\tle 24",
+			expected: "10.0.0.0/8 ge 16 le 24",
+		},
+		{
+			name:     "trailing comma",
+			text:     "10.0.0.0/8,\n192.168.0.0/16,\n",
+			expected: "10.0.0.0/8,\n192.168.0.0/16",
+		},
+		{
+			name:     "comments",
+			text:     "  # customers\n  10.0.0.0/8,\n  #   lab\n  192.168.0.0/16\n  # end of entries",
+			expected: "# customers\n10.0.0.0/8,\n# lab\n192.168.0.0/16\n# end of entries",
+		},
+		{
+			name:     "empty",
+			text:     "\n  \n",
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if normalized := NormalizePrefixSet(test.text); normalized != test.expected {
+				t.Errorf("expected\n%q\ngot\n%q", test.expected, normalized)
+			}
+		})
+	}
+}
+
+// The device reformats the policies it sends back, their bodies compare equal to the configured ones
+func TestRPLBody(t *testing.T) {
+	policy := NewCiscoRoutePolicy("customers-in", "if destination in customers then\r\n  pass\r\nendif\r\n")
+	if policy.RplRoutePolicy != "route-policy customers-in\nif destination in customers then\n  pass\nendif\nend-policy\n" {
+		t.Errorf("expected the body wrapped in route-policy and end-policy, got %q", policy.RplRoutePolicy)
+	}
+	reply := CiscoRoutePolicy{
+		RoutePolicyName: "customers-in",
+		RplRoutePolicy:  "route-policy customers-in\n  if destination in customers then\n    pass\n  endif\nend-policy\n",
+	}
+	if body := reply.Body(); body != "if destination in customers then\npass\nendif" {
+		t.Errorf("expected the body without route-policy and end-policy, got %q", body)
+	}
+
+	set := NewCiscoPrefixSet("customers", "10.0.0.0/8,\n192.168.0.0/16")
+	if set.RplPrefixSet != "prefix-set customers\n10.0.0.0/8,\n192.168.0.0/16\nend-set\n" {
+		t.Errorf("expected the body wrapped in prefix-set and end-set, got %q", set.RplPrefixSet)
+	}
+	set.RplPrefixSet = "prefix-set customers\n  10.0.0.0/8,\n  192.168.0.0/16\nend-set\n"
+	if body := set.Body(); body != "10.0.0.0/8,\n192.168.0.0/16" {
+		t.Errorf("expected the body without prefix-set and end-set, got %q", body)
+	}
+}
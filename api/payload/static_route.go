@@ -0,0 +1,86 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// CiscoStaticRoute struct represents a Cisco-IOS-XR-ip-static-cfg next hop of a prefix
+type CiscoStaticRoute struct {
+	InterfaceName  string `json:"interface-name,omitempty"`
+	NextHopAddress string `json:"next-hop-address,omitempty"`
+	Distance       int    `json:"distance,omitempty"`
+	Tag            int    `json:"tag,omitempty"`
+	Description    string `json:"description,omitempty"`
+}
+
+// staticRouteNextHopList returns the vrf-next-hop-table list for the next hop keys that are set
+func staticRouteNextHopList(interfaceName string, nextHopAddress string) string {
+	switch {
+	case interfaceName != "" && nextHopAddress != "":
+		return "vrf-next-hop-interface-name-next-hop-address"
+	case interfaceName != "":
+		return "vrf-next-hop-interface-name"
+	default:
+		return "vrf-next-hop-next-hop-address"
+	}
+}
+
+// NetconfCiscoStaticRouteURL returns netconf cisco static route URL, vrf "default" uses the default-vrf container
+func NetconfCiscoStaticRouteURL(device string, vrf string, addressFamily string, prefix string, prefixLength int, interfaceName string, nextHopAddress string) string {
+	path := ConfigPath().Mount(device).Child("Cisco-IOS-XR-ip-static-cfg:router-static")
+	if vrf == "default" {
+		path = path.Child("default-vrf")
+	} else {
+		path = path.Child("vrfs").Entry("vrf", vrf)
+	}
+
+	keys := []interface{}{}
+	if interfaceName != "" {
+		keys = append(keys, interfaceName)
+	}
+	if nextHopAddress != "" {
+		keys = append(keys, nextHopAddress)
+	}
+
+	return path.
+		Child(fmt.Sprintf("address-family/vrf%s/vrf-unicast/vrf-prefixes", addressFamily)).
+		Entry("vrf-prefix", prefix, prefixLength).
+		Child("vrf-route/vrf-next-hop-table").
+		Entry(staticRouteNextHopList(interfaceName, nextHopAddress), keys...).String()
+}
+
+// NetconfCiscoStaticRoutePayload forms a json payload for cisco static route
+func NetconfCiscoStaticRoutePayload(route CiscoStaticRoute) (bytes.Buffer, error) {
+	payloadBody := map[string][]CiscoStaticRoute{
+		staticRouteNextHopList(route.InterfaceName, route.NextHopAddress): {route},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoStaticRoutePayload parses json payload for cisco static route to a struct
+func ParseNetconfCiscoStaticRoutePayload(bodyBytes []byte) (CiscoStaticRoute, error) {
+	item := map[string][]CiscoStaticRoute{}
+	err := Unmarshal(bodyBytes, &item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoStaticRoute{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	for _, routes := range item {
+		if len(routes) > 0 {
+			return routes[0], nil
+		}
+	}
+	return CiscoStaticRoute{}, fmt.Errorf("no next hop in static route payload: %w", ErrSchemaMismatch)
+}
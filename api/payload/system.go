@@ -0,0 +1,335 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+)
+
+// ipVersion returns ipv4 or ipv6 for an address, system service lists are split by address family
+func ipVersion(address string) string {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// CiscoNtpServer struct represents a Cisco-IOS-XR-ip-ntp-cfg peer of type server
+type CiscoNtpServer struct {
+	AddressIpv4  string        `json:"address-ipv4,omitempty"`
+	AddressIpv6  string        `json:"address-ipv6,omitempty"`
+	PeerTypeIpv4 []NtpPeerType `json:"peer-type-ipv4,omitempty"`
+	PeerTypeIpv6 []NtpPeerType `json:"peer-type-ipv6,omitempty"`
+}
+
+// NtpPeerType struct
+type NtpPeerType struct {
+	PeerType        string `json:"peer-type"`
+	Prefer          *Empty `json:"prefer,omitempty"`
+	Iburst          *Empty `json:"iburst,omitempty"`
+	SourceInterface string `json:"source-interface,omitempty"`
+}
+
+// NewCiscoNtpServer returns an NTP server peer in the list for the address family of the address
+func NewCiscoNtpServer(address string, peerType NtpPeerType) CiscoNtpServer {
+	peerType.PeerType = "server"
+	if ipVersion(address) == "ipv6" {
+		return CiscoNtpServer{AddressIpv6: address, PeerTypeIpv6: []NtpPeerType{peerType}}
+	}
+	return CiscoNtpServer{AddressIpv4: address, PeerTypeIpv4: []NtpPeerType{peerType}}
+}
+
+// Server returns the server peer type of the NTP peer
+func (s CiscoNtpServer) Server() (NtpPeerType, bool) {
+	for _, peerType := range append(s.PeerTypeIpv4, s.PeerTypeIpv6...) {
+		if peerType.PeerType == "server" {
+			return peerType, true
+		}
+	}
+	return NtpPeerType{}, false
+}
+
+// CiscoLoggingHost struct represents a Cisco-IOS-XR-infra-syslog-cfg host server
+type CiscoLoggingHost struct {
+	Address          string               `json:"address"`
+	Ipv4SeverityPort *LoggingSeverityPort `json:"ipv4-severity-port,omitempty"`
+	Ipv6SeverityPort *LoggingSeverityPort `json:"ipv6-severity-port,omitempty"`
+}
+
+// LoggingSeverityPort struct
+type LoggingSeverityPort struct {
+	Severity int `json:"severity,omitempty"`
+	Port     int `json:"port,omitempty"`
+}
+
+// NewCiscoLoggingHost returns a logging host in the list for the address family of the address
+func NewCiscoLoggingHost(address string, severityPort LoggingSeverityPort) CiscoLoggingHost {
+	if ipVersion(address) == "ipv6" {
+		return CiscoLoggingHost{Address: address, Ipv6SeverityPort: &severityPort}
+	}
+	return CiscoLoggingHost{Address: address, Ipv4SeverityPort: &severityPort}
+}
+
+// SeverityPort returns the severity and port of the logging host
+func (h CiscoLoggingHost) SeverityPort() LoggingSeverityPort {
+	if h.Ipv4SeverityPort != nil {
+		return *h.Ipv4SeverityPort
+	}
+	if h.Ipv6SeverityPort != nil {
+		return *h.Ipv6SeverityPort
+	}
+	return LoggingSeverityPort{}
+}
+
+// CiscoSnmpCommunityPayload struct
+type CiscoSnmpCommunityPayload struct {
+	Node []CiscoSnmpCommunity `json:"default-community"`
+}
+
+// CiscoSnmpCommunity struct represents a Cisco-IOS-XR-snmp-agent-cfg default community
+type CiscoSnmpCommunity struct {
+	CommunityName string `json:"community-name"`
+	Priviledge    string `json:"priviledge,omitempty"`
+	ViewName      string `json:"view-name,omitempty"`
+	V4AccessList  string `json:"v4-access-list,omitempty"`
+}
+
+// CiscoSnmpTrapHostPayload struct
+type CiscoSnmpTrapHostPayload struct {
+	Node []CiscoSnmpTrapHost `json:"trap-host"`
+}
+
+// CiscoSnmpTrapHost struct represents a Cisco-IOS-XR-snmp-agent-cfg trap host
+type CiscoSnmpTrapHost struct {
+	IPAddress              string                     `json:"ip-address"`
+	DefaultUserCommunities SnmpDefaultUserCommunities `json:"default-user-communities"`
+}
+
+// SnmpDefaultUserCommunities struct
+type SnmpDefaultUserCommunities struct {
+	DefaultUserCommunity []SnmpDefaultUserCommunity `json:"default-user-community"`
+}
+
+// SnmpDefaultUserCommunity struct
+type SnmpDefaultUserCommunity struct {
+	CommunityName  string `json:"community-name"`
+	Version        string `json:"version,omitempty"`
+	BasicTrapTypes int    `json:"basic-trap-types"`
+}
+
+// CiscoNameServerPayload struct
+type CiscoNameServerPayload struct {
+	Node []CiscoNameServer `json:"server"`
+}
+
+// CiscoNameServer struct represents a Cisco-IOS-XR-ip-domain-cfg name server
+type CiscoNameServer struct {
+	Order         int    `json:"order"`
+	ServerAddress string `json:"server-address"`
+}
+
+// NetconfCiscoNtpServerURL returns netconf cisco NTP peer URL
+func NetconfCiscoNtpServerURL(device string, vrf string, address string) string {
+	version := ipVersion(address)
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-ip-ntp-cfg:ntp/peer-vrfs").
+		Entry("peer-vrf", vrf).
+		Child(fmt.Sprintf("peer-%ss", version)).
+		Entry("peer-"+version, address).String()
+}
+
+// NetconfCiscoLoggingHostURL returns netconf cisco syslog host server URL
+func NetconfCiscoLoggingHostURL(device string, vrf string, address string) string {
+	version := ipVersion(address)
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-infra-syslog-cfg:syslog/host-server/vrfs").
+		Entry("vrf", vrf).
+		Child(version+"s").
+		Entry(version, address).String()
+}
+
+// NetconfCiscoSnmpCommunityURL returns netconf cisco SNMP community URL
+func NetconfCiscoSnmpCommunityURL(device string, community string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-snmp-agent-cfg:snmp/administration/default-communities").
+		Entry("default-community", community).String()
+}
+
+// NetconfCiscoSnmpTrapHostURL returns netconf cisco SNMP trap host URL
+func NetconfCiscoSnmpTrapHostURL(device string, address string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-snmp-agent-cfg:snmp/trap-hosts").
+		Entry("trap-host", address).String()
+}
+
+// NetconfCiscoNameServerURL returns netconf cisco domain name server URL
+func NetconfCiscoNameServerURL(device string, vrf string, order int, address string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-ip-domain-cfg:ip-domain/vrfs").
+		Entry("vrf", vrf).
+		Child("servers").
+		Entry("server", order, address).String()
+}
+
+// NetconfCiscoNtpServerPayload forms a json payload for cisco NTP peer
+func NetconfCiscoNtpServerPayload(server CiscoNtpServer) (bytes.Buffer, error) {
+	list := "peer-ipv4"
+	if server.AddressIpv6 != "" {
+		list = "peer-ipv6"
+	}
+	payloadBody := map[string][]CiscoNtpServer{
+		list: {server},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoNtpServerPayload parses json payload for cisco NTP peer to a struct
+func ParseNetconfCiscoNtpServerPayload(bodyBytes []byte) (CiscoNtpServer, error) {
+	item := map[string][]CiscoNtpServer{}
+	err := Unmarshal(bodyBytes, &item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoNtpServer{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	for _, servers := range item {
+		if len(servers) > 0 {
+			return servers[0], nil
+		}
+	}
+	return CiscoNtpServer{}, fmt.Errorf("no peer in NTP payload: %w", ErrSchemaMismatch)
+}
+
+// NetconfCiscoLoggingHostPayload forms a json payload for cisco syslog host server
+func NetconfCiscoLoggingHostPayload(host CiscoLoggingHost) (bytes.Buffer, error) {
+	list := "ipv4"
+	if host.Ipv6SeverityPort != nil {
+		list = "ipv6"
+	}
+	payloadBody := map[string][]CiscoLoggingHost{
+		list: {host},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoLoggingHostPayload parses json payload for cisco syslog host server to a struct
+func ParseNetconfCiscoLoggingHostPayload(bodyBytes []byte) (CiscoLoggingHost, error) {
+	item := map[string][]CiscoLoggingHost{}
+	err := Unmarshal(bodyBytes, &item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoLoggingHost{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	for _, hosts := range item {
+		if len(hosts) > 0 {
+			return hosts[0], nil
+		}
+	}
+	return CiscoLoggingHost{}, fmt.Errorf("no host in syslog payload: %w", ErrSchemaMismatch)
+}
+
+// NetconfCiscoSnmpCommunityPayload forms a json payload for cisco SNMP community
+func NetconfCiscoSnmpCommunityPayload(community CiscoSnmpCommunity) (bytes.Buffer, error) {
+	payloadBody := CiscoSnmpCommunityPayload{
+		Node: []CiscoSnmpCommunity{community},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoSnmpCommunityPayload parses json payload for cisco SNMP community to a struct
+func ParseNetconfCiscoSnmpCommunityPayload(bodyBytes []byte) (CiscoSnmpCommunity, error) {
+	item := &CiscoSnmpCommunityPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoSnmpCommunity{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var community CiscoSnmpCommunity = item.Node[0]
+	return community, nil
+}
+
+// NetconfCiscoSnmpTrapHostPayload forms a json payload for cisco SNMP trap host
+func NetconfCiscoSnmpTrapHostPayload(host CiscoSnmpTrapHost) (bytes.Buffer, error) {
+	payloadBody := CiscoSnmpTrapHostPayload{
+		Node: []CiscoSnmpTrapHost{host},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoSnmpTrapHostPayload parses json payload for cisco SNMP trap host to a struct
+func ParseNetconfCiscoSnmpTrapHostPayload(bodyBytes []byte) (CiscoSnmpTrapHost, error) {
+	item := &CiscoSnmpTrapHostPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoSnmpTrapHost{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var host CiscoSnmpTrapHost = item.Node[0]
+	return host, nil
+}
+
+// NetconfCiscoNameServerPayload forms a json payload for cisco domain name server
+func NetconfCiscoNameServerPayload(server CiscoNameServer) (bytes.Buffer, error) {
+	payloadBody := CiscoNameServerPayload{
+		Node: []CiscoNameServer{server},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoNameServerPayload parses json payload for cisco domain name server to a struct
+func ParseNetconfCiscoNameServerPayload(bodyBytes []byte) (CiscoNameServer, error) {
+	item := &CiscoNameServerPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoNameServer{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var server CiscoNameServer = item.Node[0]
+	return server, nil
+}
@@ -0,0 +1,278 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CiscoVrfPayload struct
+type CiscoVrfPayload struct {
+	Node []CiscoVrf `json:"vrf"`
+}
+
+// CiscoVrf struct represents a Cisco-IOS-XR-infra-rsi-cfg vrf
+type CiscoVrf struct {
+	VrfName     string  `json:"vrf-name"`
+	Create      *Empty  `json:"create,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Afs         *VrfAfs `json:"afs,omitempty"`
+}
+
+// VrfAfs struct
+type VrfAfs struct {
+	Af []VrfAf `json:"af"`
+}
+
+// VrfAf struct is a vrf address family, route targets hang off it
+type VrfAf struct {
+	AfName       string    `json:"af-name"`
+	SafName      string    `json:"saf-name"`
+	TopologyName string    `json:"topology-name"`
+	Create       *Empty    `json:"create,omitempty"`
+	Bgp          *VrfAfBgp `json:"Cisco-IOS-XR-ipv4-bgp-cfg:bgp,omitempty"`
+}
+
+// VrfAfBgp struct
+type VrfAfBgp struct {
+	ImportRouteTargets *RouteTargets `json:"import-route-targets,omitempty"`
+	ExportRouteTargets *RouteTargets `json:"export-route-targets,omitempty"`
+}
+
+// RouteTargets struct
+type RouteTargets struct {
+	RouteTargets RouteTargetList `json:"route-targets"`
+}
+
+// RouteTargetList struct
+type RouteTargetList struct {
+	RouteTarget []RouteTarget `json:"route-target"`
+}
+
+// RouteTarget struct groups route targets by their type
+type RouteTarget struct {
+	Type           string            `json:"type"`
+	AsOrFourByteAs []RouteTargetAs   `json:"as-or-four-byte-as,omitempty"`
+	Ipv4Address    []RouteTargetIpv4 `json:"ipv4-address,omitempty"`
+}
+
+// RouteTargetAs struct is an ASN:nn route target
+type RouteTargetAs struct {
+	AsXx        int `json:"as-xx"`
+	As          int `json:"as"`
+	AsIndex     int `json:"as-index"`
+	StitchingRt int `json:"stitching-rt"`
+}
+
+// RouteTargetIpv4 struct is an IPv4:nn route target
+type RouteTargetIpv4 struct {
+	Address      string `json:"address"`
+	AddressIndex int    `json:"address-index"`
+	StitchingRt  int    `json:"stitching-rt"`
+}
+
+// CiscoBgpVrfPayload struct
+type CiscoBgpVrfPayload struct {
+	Node []CiscoBgpVrf `json:"vrf"`
+}
+
+// CiscoBgpVrf struct represents the BGP side of a vrf, which holds the route distinguisher
+type CiscoBgpVrf struct {
+	VrfName   string       `json:"vrf-name"`
+	VrfGlobal BgpVrfGlobal `json:"vrf-global"`
+}
+
+// BgpVrfGlobal struct
+type BgpVrfGlobal struct {
+	Exists             *Empty              `json:"exists,omitempty"`
+	RouteDistinguisher *RouteDistinguisher `json:"route-distinguisher,omitempty"`
+	VrfGlobalAfs       *BgpVrfGlobalAfs    `json:"vrf-global-afs,omitempty"`
+}
+
+// RouteDistinguisher struct
+type RouteDistinguisher struct {
+	Type         string `json:"type"`
+	AsXx         int    `json:"as-xx,omitempty"`
+	As           int    `json:"as,omitempty"`
+	AsIndex      int    `json:"as-index,omitempty"`
+	Address      string `json:"address,omitempty"`
+	AddressIndex int    `json:"address-index,omitempty"`
+}
+
+// BgpVrfGlobalAfs struct
+type BgpVrfGlobalAfs struct {
+	VrfGlobalAf []BgpVrfGlobalAf `json:"vrf-global-af"`
+}
+
+// BgpVrfGlobalAf struct
+type BgpVrfGlobalAf struct {
+	AfName string `json:"af-name"`
+	Enable *Empty `json:"enable,omitempty"`
+}
+
+// NetconfCiscoVrfURL returns netconf cisco vrf URL
+func NetconfCiscoVrfURL(device string, vrf string) string {
+	return ConfigPath().Mount(device).
+		Child("Cisco-IOS-XR-infra-rsi-cfg:vrfs").
+		Entry("vrf", vrf).String()
+}
+
+// NetconfCiscoBgpVrfURL returns netconf cisco BGP vrf URL for a BGP instance AS
+func NetconfCiscoBgpVrfURL(device string, as int, vrf string) string {
+	return bgpInstancePath(device, as).
+		Child("vrfs").
+		Entry("vrf", vrf).String()
+}
+
+// NetconfCiscoVrfPayload forms a json payload for cisco vrf
+func NetconfCiscoVrfPayload(vrf CiscoVrf) (bytes.Buffer, error) {
+	payloadBody := CiscoVrfPayload{
+		Node: []CiscoVrf{vrf},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoVrfPayload parses json payload for cisco vrf to a struct
+func ParseNetconfCiscoVrfPayload(bodyBytes []byte) (CiscoVrf, error) {
+	item := &CiscoVrfPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoVrf{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var vrf CiscoVrf = item.Node[0]
+	return vrf, nil
+}
+
+// NetconfCiscoBgpVrfPayload forms a json payload for cisco BGP vrf
+func NetconfCiscoBgpVrfPayload(vrf CiscoBgpVrf) (bytes.Buffer, error) {
+	payloadBody := CiscoBgpVrfPayload{
+		Node: []CiscoBgpVrf{vrf},
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfCiscoBgpVrfPayload parses json payload for cisco BGP vrf to a struct
+func ParseNetconfCiscoBgpVrfPayload(bodyBytes []byte) (CiscoBgpVrf, error) {
+	item := &CiscoBgpVrfPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return CiscoBgpVrf{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	var vrf CiscoBgpVrf = item.Node[0]
+	return vrf, nil
+}
+
+// splitExtendedCommunity splits an ASN:nn or IPv4:nn value used by route targets and distinguishers
+func splitExtendedCommunity(value string) (string, int, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 1 {
+		return "", 0, fmt.Errorf("%q is not in ASN:nn or IPv4:nn format", value)
+	}
+	index, err := strconv.Atoi(value[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("%q is not in ASN:nn or IPv4:nn format", value)
+	}
+	return value[:i], index, nil
+}
+
+// ParseRouteDistinguisher parses an ASN:nn or IPv4:nn route distinguisher
+func ParseRouteDistinguisher(value string) (RouteDistinguisher, error) {
+	admin, index, err := splitExtendedCommunity(value)
+	if err != nil {
+		return RouteDistinguisher{}, err
+	}
+	if net.ParseIP(admin).To4() != nil {
+		return RouteDistinguisher{Type: "ipv4-address", Address: admin, AddressIndex: index}, nil
+	}
+	as, err := strconv.Atoi(admin)
+	if err != nil {
+		return RouteDistinguisher{}, fmt.Errorf("%q is not in ASN:nn or IPv4:nn format", value)
+	}
+	if as > 65535 {
+		return RouteDistinguisher{Type: "four-byte-as", As: as, AsIndex: index}, nil
+	}
+	return RouteDistinguisher{Type: "as", As: as, AsIndex: index}, nil
+}
+
+// String formats a route distinguisher as ASN:nn or IPv4:nn
+func (rd RouteDistinguisher) String() string {
+	if rd.Type == "ipv4-address" {
+		return fmt.Sprintf("%s:%d", rd.Address, rd.AddressIndex)
+	}
+	return fmt.Sprintf("%d:%d", rd.As, rd.AsIndex)
+}
+
+// NewRouteTargets builds the route target list from ASN:nn or IPv4:nn values
+func NewRouteTargets(values []string) (*RouteTargets, error) {
+	byType := map[string]*RouteTarget{}
+	for _, value := range values {
+		rd, err := ParseRouteDistinguisher(value)
+		if err != nil {
+			return nil, err
+		}
+		rt, ok := byType[rd.Type]
+		if !ok {
+			rt = &RouteTarget{Type: rd.Type}
+			byType[rd.Type] = rt
+		}
+		if rd.Type == "ipv4-address" {
+			rt.Ipv4Address = append(rt.Ipv4Address, RouteTargetIpv4{Address: rd.Address, AddressIndex: rd.AddressIndex})
+		} else {
+			rt.AsOrFourByteAs = append(rt.AsOrFourByteAs, RouteTargetAs{As: rd.As, AsIndex: rd.AsIndex})
+		}
+	}
+
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	rts := &RouteTargets{}
+	for _, t := range types {
+		rts.RouteTargets.RouteTarget = append(rts.RouteTargets.RouteTarget, *byType[t])
+	}
+	return rts, nil
+}
+
+// Strings returns the route targets as ASN:nn or IPv4:nn values
+func (rts *RouteTargets) Strings() []string {
+	values := []string{}
+	if rts == nil {
+		return values
+	}
+	for _, rt := range rts.RouteTargets.RouteTarget {
+		for _, as := range rt.AsOrFourByteAs {
+			values = append(values, fmt.Sprintf("%d:%d", as.As, as.AsIndex))
+		}
+		for _, ip := range rt.Ipv4Address {
+			values = append(values, fmt.Sprintf("%s:%d", ip.Address, ip.AddressIndex))
+		}
+	}
+	return values
+}
@@ -0,0 +1,234 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// YangPatchPayload struct
+type YangPatchPayload struct {
+	YangPatch YangPatch `json:"ietf-yang-patch:yang-patch"`
+}
+
+// YangPatch struct represents an RFC 8072 YANG-Patch, the edits are applied in one transaction
+type YangPatch struct {
+	PatchID string          `json:"patch-id"`
+	Comment string          `json:"comment,omitempty"`
+	Edit    []YangPatchEdit `json:"edit"`
+}
+
+// YangPatchEdit struct, the target is relative to the url the patch is sent to. Where and
+// point position the entries of insert and move edits in user ordered lists
+type YangPatchEdit struct {
+	EditID    string          `json:"edit-id"`
+	Operation string          `json:"operation"`
+	Target    string          `json:"target"`
+	Point     string          `json:"point,omitempty"`
+	Where     string          `json:"where,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty"`
+}
+
+// mountPoint is the segment of a url below which the configuration of a mounted device starts
+const mountPoint = "/yang-ext:mount"
+
+// SplitMountURL splits a config url below a mount into the url of the mount and the
+// target below it, so edits to a device can be sent as a YANG-Patch to its mount
+func SplitMountURL(url string) (mountURL string, target string, ok bool) {
+	if !strings.HasPrefix(url, "restconf/config/") {
+		return "", "", false
+	}
+	i := strings.Index(url, mountPoint+"/")
+	if i < 0 {
+		return "", "", false
+	}
+	return url[:i+len(mountPoint)], url[i+len(mountPoint):], true
+}
+
+// NewYangPatch returns a YANG-Patch without edits
+func NewYangPatch(patchID string) *YangPatch {
+	return &YangPatch{PatchID: patchID}
+}
+
+// Append adds an edit to the patch, edits without an edit-id are numbered in order
+func (p *YangPatch) Append(edit YangPatchEdit) *YangPatch {
+	if edit.EditID == "" {
+		edit.EditID = fmt.Sprintf("edit-%d", len(p.Edit)+1)
+	}
+	p.Edit = append(p.Edit, edit)
+	return p
+}
+
+// edit appends an edit with a json payload as its value
+func (p *YangPatch) edit(operation string, target string, value *bytes.Buffer, where string, point string) *YangPatch {
+	edit := YangPatchEdit{
+		Operation: operation,
+		Target:    target,
+		Where:     where,
+		Point:     point,
+	}
+	if value != nil && value.Len() > 0 {
+		edit.Value = json.RawMessage(value.Bytes())
+	}
+	return p.Append(edit)
+}
+
+// Create adds an edit creating the target, the patch fails if it already exists
+func (p *YangPatch) Create(target string, value bytes.Buffer) *YangPatch {
+	return p.edit("create", target, &value, "", "")
+}
+
+// Merge adds an edit merging the value into the target
+func (p *YangPatch) Merge(target string, value bytes.Buffer) *YangPatch {
+	return p.edit("merge", target, &value, "", "")
+}
+
+// Replace adds an edit replacing the target with the value
+func (p *YangPatch) Replace(target string, value bytes.Buffer) *YangPatch {
+	return p.edit("replace", target, &value, "", "")
+}
+
+// Insert adds an edit inserting an entry into a user ordered list, where is first, last,
+// before or after and point is the entry it is inserted before or after
+func (p *YangPatch) Insert(target string, value bytes.Buffer, where string, point string) *YangPatch {
+	return p.edit("insert", target, &value, where, point)
+}
+
+// Move adds an edit moving an entry of a user ordered list, where and point are as for Insert
+func (p *YangPatch) Move(target string, where string, point string) *YangPatch {
+	return p.edit("move", target, nil, where, point)
+}
+
+// Delete adds an edit deleting the target, the patch fails if it doesn't exist
+func (p *YangPatch) Delete(target string) *YangPatch {
+	return p.edit("delete", target, nil, "", "")
+}
+
+// Remove adds an edit removing the target if it exists
+func (p *YangPatch) Remove(target string) *YangPatch {
+	return p.edit("remove", target, nil, "", "")
+}
+
+// YangPatchStatusPayload struct
+type YangPatchStatusPayload struct {
+	YangPatchStatus YangPatchStatus `json:"ietf-yang-patch:yang-patch-status"`
+}
+
+// YangPatchStatus struct is the reply to a YANG-Patch
+type YangPatchStatus struct {
+	PatchID    string               `json:"patch-id"`
+	Ok         *Empty               `json:"ok,omitempty"`
+	Errors     *YangPatchErrors     `json:"errors,omitempty"`
+	EditStatus *YangPatchEditStatus `json:"edit-status,omitempty"`
+}
+
+// YangPatchEditStatus struct
+type YangPatchEditStatus struct {
+	Edit []YangPatchEditResult `json:"edit"`
+}
+
+// YangPatchEditResult struct is the status of a single edit
+type YangPatchEditResult struct {
+	EditID string           `json:"edit-id"`
+	Ok     *Empty           `json:"ok,omitempty"`
+	Errors *YangPatchErrors `json:"errors,omitempty"`
+}
+
+// YangPatchErrors struct
+type YangPatchErrors struct {
+	Error []YangPatchError `json:"error"`
+}
+
+// YangPatchError struct
+type YangPatchError struct {
+	ErrorType    string `json:"error-type"`
+	ErrorTag     string `json:"error-tag"`
+	ErrorPath    string `json:"error-path,omitempty"`
+	ErrorMessage string `json:"error-message,omitempty"`
+}
+
+func (e YangPatchError) String() string {
+	message := e.ErrorTag
+	if e.ErrorMessage != "" {
+		message = fmt.Sprintf("%s: %s", message, e.ErrorMessage)
+	}
+	if e.ErrorPath != "" {
+		message = fmt.Sprintf("%s at %s", message, e.ErrorPath)
+	}
+	return message
+}
+
+// EditErr returns the errors of a single edit, or nil when the edit didn't fail
+func (s YangPatchStatus) EditErr(editID string) error {
+	if s.EditStatus == nil {
+		return nil
+	}
+	for _, edit := range s.EditStatus.Edit {
+		if edit.EditID != editID || edit.Errors == nil {
+			continue
+		}
+		failures := []string{}
+		for _, e := range edit.Errors.Error {
+			failures = append(failures, e.String())
+		}
+		return fmt.Errorf("edit %s of yang-patch %s failed: %s", editID, s.PatchID, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Err returns an error listing every failed edit of the patch, or nil when the patch was applied
+func (s YangPatchStatus) Err() error {
+	failures := []string{}
+	if s.Errors != nil {
+		for _, e := range s.Errors.Error {
+			failures = append(failures, e.String())
+		}
+	}
+	if s.EditStatus != nil {
+		for _, edit := range s.EditStatus.Edit {
+			if edit.Errors == nil {
+				continue
+			}
+			for _, e := range edit.Errors.Error {
+				failures = append(failures, fmt.Sprintf("edit %s: %s", edit.EditID, e.String()))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("yang-patch %s failed: %s", s.PatchID, strings.Join(failures, "; "))
+	}
+	if s.Ok == nil && s.EditStatus == nil {
+		return fmt.Errorf("yang-patch %s was not acknowledged", s.PatchID)
+	}
+	return nil
+}
+
+// NetconfYangPatchPayload forms a json YANG-Patch payload
+func NetconfYangPatchPayload(patch YangPatch) (bytes.Buffer, error) {
+	payloadBody := YangPatchPayload{
+		YangPatch: patch,
+	}
+
+	buf := bytes.Buffer{}
+	err := json.NewEncoder(&buf).Encode(payloadBody)
+	if err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+// ParseNetconfYangPatchStatus parses the json reply to a YANG-Patch to a struct
+func ParseNetconfYangPatchStatus(bodyBytes []byte) (YangPatchStatus, error) {
+	item := &YangPatchStatusPayload{}
+	err := Unmarshal(bodyBytes, item)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return YangPatchStatus{}, err
+	}
+
+	log.Printf("[DEBUG] Parsed Body: %v", item)
+
+	return item.YangPatchStatus, nil
+}
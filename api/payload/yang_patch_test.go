@@ -0,0 +1,123 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYangPatchBuilder(t *testing.T) {
+	patch := NewYangPatch("update").
+		Create("/a", *bytes.NewBufferString(`{"a":[]}`)).
+		Merge("/b", *bytes.NewBufferString(`{"b":[]}`)).
+		Replace("/c", *bytes.NewBufferString(`{"c":[]}`)).
+		Insert("/d=2", *bytes.NewBufferString(`{"d":[]}`), "after", "/d=1").
+		Move("/d=3", "first", "").
+		Delete("/e").
+		Remove("/f").
+		Append(YangPatchEdit{EditID: "named", Operation: "remove", Target: "/g"}).
+		Merge("/h", bytes.Buffer{})
+
+	expected := []YangPatchEdit{
+		{EditID: "edit-1", Operation: "create", Target: "/a", Value: json.RawMessage(`{"a":[]}`)},
+		{EditID: "edit-2", Operation: "merge", Target: "/b", Value: json.RawMessage(`{"b":[]}`)},
+		{EditID: "edit-3", Operation: "replace", Target: "/c", Value: json.RawMessage(`{"c":[]}`)},
+		{EditID: "edit-4", Operation: "insert", Target: "/d=2", Where: "after", Point: "/d=1", Value: json.RawMessage(`{"d":[]}`)},
+		{EditID: "edit-5", Operation: "move", Target: "/d=3", Where: "first"},
+		{EditID: "edit-6", Operation: "delete", Target: "/e"},
+		{EditID: "edit-7", Operation: "remove", Target: "/f"},
+		{EditID: "named", Operation: "remove", Target: "/g"},
+		{EditID: "edit-9", Operation: "merge", Target: "/h"},
+	}
+	if patch.PatchID != "update" {
+		t.Errorf("expected patch-id update, got %s", patch.PatchID)
+	}
+	if !reflect.DeepEqual(patch.Edit, expected) {
+		t.Errorf("expected edits\n%+v\ngot\n%+v", expected, patch.Edit)
+	}
+}
+
+func TestNetconfYangPatchPayload(t *testing.T) {
+	patch := NewYangPatch("update").
+		Merge("/vrfs", *bytes.NewBufferString(`{"vrfs":{}}`)).
+		Remove("/vrfs/vrf=blue")
+
+	body, err := NetconfYangPatchPayload(*patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"ietf-yang-patch:yang-patch":{"patch-id":"update","edit":[` +
+		`{"edit-id":"edit-1","operation":"merge","target":"/vrfs","value":{"vrfs":{}}},` +
+		`{"edit-id":"edit-2","operation":"remove","target":"/vrfs/vrf=blue"}]}}`
+	if got := strings.TrimSpace(body.String()); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestParseNetconfYangPatchStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		err      string
+		editErrs map[string]string
+	}{
+		{
+			name: "ok",
+			body: `{"ietf-yang-patch:yang-patch-status":{"patch-id":"update","ok":[null]}}`,
+		},
+		{
+			name: "edits ok",
+			body: `{"ietf-yang-patch:yang-patch-status":{"patch-id":"update","edit-status":{"edit":[` +
+				`{"edit-id":"edit-1","ok":[null]},{"edit-id":"edit-2","ok":[null]}]}}}`,
+			editErrs: map[string]string{"edit-1": "", "edit-2": ""},
+		},
+		{
+			name: "edit failed",
+			body: `{"ietf-yang-patch:yang-patch-status":{"patch-id":"update","edit-status":{"edit":[` +
+				`{"edit-id":"edit-1","ok":[null]},` +
+				`{"edit-id":"edit-2","errors":{"error":[{"error-type":"application","error-tag":"data-missing",` +
+				`"error-path":"/vrfs/vrf=blue","error-message":"Data does not exist"}]}}]}}}`,
+			err: "yang-patch update failed: edit edit-2: data-missing: Data does not exist at /vrfs/vrf=blue",
+			editErrs: map[string]string{
+				"edit-1": "",
+				"edit-2": "edit edit-2 of yang-patch update failed: data-missing: Data does not exist at /vrfs/vrf=blue",
+				"edit-3": "",
+			},
+		},
+		{
+			name: "patch failed",
+			body: `{"ietf-yang-patch:yang-patch-status":{"patch-id":"update","errors":{"error":[` +
+				`{"error-type":"protocol","error-tag":"lock-denied"}]}}}`,
+			err:      "yang-patch update failed: lock-denied",
+			editErrs: map[string]string{"edit-1": ""},
+		},
+		{
+			name: "not acknowledged",
+			body: `{"ietf-yang-patch:yang-patch-status":{"patch-id":"update"}}`,
+			err:  "yang-patch update was not acknowledged",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status, err := ParseNetconfYangPatchStatus([]byte(c.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.PatchID != "update" {
+				t.Errorf("expected patch-id update, got %s", status.PatchID)
+			}
+			if err := status.Err(); (err == nil && c.err != "") || (err != nil && err.Error() != c.err) {
+				t.Errorf("expected error %q, got %v", c.err, err)
+			}
+			for editID, expected := range c.editErrs {
+				err := status.EditErr(editID)
+				if (err == nil && expected != "") || (err != nil && err.Error() != expected) {
+					t.Errorf("expected error %q for %s, got %v", expected, editID, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Code generated by yanggen from YANG modules. DO NOT EDIT.
+
+package payload
+
+// Generated from
+//   Cisco-IOS-XR-ifmgr-cfg@2017-09-07
+//   Cisco-IOS-XR-infra-rsi-cfg@2017-09-07
+//   Cisco-IOS-XR-l2-eth-infra-cfg@2017-05-01
+//   Cisco-IOS-XR-l2vpn-cfg@2017-06-26
+//   Cisco-IOS-XR-qos-ma-cfg@2017-05-01
+//   netconf-node-topology@2015-01-14
+//   network-topology@2013-10-21
+
+// InterfaceConfiguration is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration
+//
+// The configuration for an interface
+type InterfaceConfiguration struct {
+	// The MTU configuration for the interface
+	Mtus *InterfaceConfigurationMtus `json:"mtus,omitempty"`
+	// The description of this interface
+	Description string `json:"description,omitempty"`
+	// The mode in which an interface is running.
+	InterfaceModeNonPhysical InterfaceModeEnum `json:"interface-mode-non-physical,omitempty"`
+	// Whether the interface is active or preconfigured
+	Active string `json:"active"`
+	// The name of the interface
+	InterfaceName string `json:"interface-name"`
+	// Ethernet Service Configuration
+	EthernetService *InterfaceConfigurationEthernetService `json:"Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service,omitempty"`
+	// Assign the interface to a VRF
+	Vrf string `json:"Cisco-IOS-XR-infra-rsi-cfg:vrf,omitempty" yang:"length=1..32"`
+	// Interface QOS configuration
+	Qos *InterfaceConfigurationQos `json:"Cisco-IOS-XR-qos-ma-cfg:qos,omitempty"`
+}
+
+// InterfaceConfigurationMtus is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/mtus
+//
+// The MTU configuration for the interface
+type InterfaceConfigurationMtus struct {
+	// The MTU for the interface
+	Mtu []InterfaceConfigurationMtusMtu `json:"mtu,omitempty"`
+}
+
+// InterfaceConfigurationMtusMtu is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/mtus/mtu
+//
+// The MTU for the interface
+type InterfaceConfigurationMtusMtu struct {
+	// The Owner of the interface, ie loopback for the main interface of LoopbackX
+	Owner string `json:"owner"`
+	// The MTU value
+	Mtu uint32 `json:"mtu,omitempty" yang:"range=64..65535"`
+}
+
+// InterfaceConfigurationEthernetService is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/ethernet-service
+//
+// Ethernet Service Configuration
+type InterfaceConfigurationEthernetService struct {
+	// Ethernet encapsulation for the interface
+	Encapsulation *InterfaceConfigurationEthernetServiceEncapsulation `json:"encapsulation,omitempty"`
+	// Rewrite the tags of packets received on the interface
+	Rewrite *InterfaceConfigurationEthernetServiceRewrite `json:"rewrite,omitempty"`
+}
+
+// InterfaceConfigurationEthernetServiceEncapsulation is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/ethernet-service/encapsulation
+//
+// Ethernet encapsulation for the interface
+type InterfaceConfigurationEthernetServiceEncapsulation struct {
+	// Whether to match untagged packets, Dot1Q tagged packets or Dot1ad tagged packets
+	OuterTagType Match `json:"outer-tag-type,omitempty"`
+}
+
+// InterfaceConfigurationEthernetServiceRewrite is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/ethernet-service/rewrite
+//
+// Rewrite the tags of packets received on the interface
+type InterfaceConfigurationEthernetServiceRewrite struct {
+	// Type of rewrite
+	RewriteType Rewrite `json:"rewrite-type,omitempty"`
+	// Type of outermost tag to push
+	OuterTagType Match `json:"outer-tag-type,omitempty"`
+	// Value of outermost tag to push
+	OuterTagValue uint32 `json:"outer-tag-value,omitempty" yang:"range=1..4094"`
+	// Type of innermost tag to push
+	InnerTagType Match `json:"inner-tag-type,omitempty"`
+	// Value of innermost tag to push
+	InnerTagValue uint32 `json:"inner-tag-value,omitempty" yang:"range=1..4094"`
+}
+
+// InterfaceConfigurationQos is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/qos
+//
+// Interface QOS configuration
+type InterfaceConfigurationQos struct {
+	// Ingress service policy
+	Input *InterfaceConfigurationQosInput `json:"input,omitempty"`
+	// Egress service policy
+	Output *InterfaceConfigurationQosOutput `json:"output,omitempty"`
+}
+
+// InterfaceConfigurationQosInput is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/qos/input
+//
+// Ingress service policy
+type InterfaceConfigurationQosInput struct {
+	// Service policy details
+	ServicePolicy []InterfaceConfigurationQosInputServicePolicy `json:"service-policy,omitempty"`
+}
+
+// InterfaceConfigurationQosInputServicePolicy is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/qos/input/service-policy
+//
+// Service policy details
+type InterfaceConfigurationQosInputServicePolicy struct {
+	// Name of policy-map
+	ServicePolicyName string `json:"service-policy-name" yang:"length=1..64"`
+}
+
+// InterfaceConfigurationQosOutput is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/qos/output
+//
+// Egress service policy
+type InterfaceConfigurationQosOutput struct {
+	// Service policy details
+	ServicePolicy []InterfaceConfigurationQosOutputServicePolicy `json:"service-policy,omitempty"`
+}
+
+// InterfaceConfigurationQosOutputServicePolicy is /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration/qos/output/service-policy
+//
+// Service policy details
+type InterfaceConfigurationQosOutputServicePolicy struct {
+	// Name of policy-map
+	ServicePolicyName string `json:"service-policy-name" yang:"length=1..64"`
+}
+
+// VlanAwareFlexibleXconnectService is /Cisco-IOS-XR-l2vpn-cfg:l2vpn/database/flexible-xconnect-service-table/vlan-aware-flexible-xconnect-services/vlan-aware-flexible-xconnect-service
+//
+// Flexible XConnect Service
+type VlanAwareFlexibleXconnectService struct {
+	// List of attachment circuits
+	VlanAwareFxcAttachmentCircuits *VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuits `json:"vlan-aware-fxc-attachment-circuits,omitempty"`
+	// Ethernet VPN ID
+	Eviid uint32 `json:"eviid" yang:"range=1..65534"`
+}
+
+// VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuits is /Cisco-IOS-XR-l2vpn-cfg:l2vpn/database/flexible-xconnect-service-table/vlan-aware-flexible-xconnect-services/vlan-aware-flexible-xconnect-service/vlan-aware-fxc-attachment-circuits
+//
+// List of attachment circuits
+type VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuits struct {
+	// Attachment circuit interface
+	VlanAwareFxcAttachmentCircuit []VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuitsVlanAwareFxcAttachmentCircuit `json:"vlan-aware-fxc-attachment-circuit,omitempty"`
+}
+
+// VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuitsVlanAwareFxcAttachmentCircuit is /Cisco-IOS-XR-l2vpn-cfg:l2vpn/database/flexible-xconnect-service-table/vlan-aware-flexible-xconnect-services/vlan-aware-flexible-xconnect-service/vlan-aware-fxc-attachment-circuits/vlan-aware-fxc-attachment-circuit
+//
+// Attachment circuit interface
+type VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuitsVlanAwareFxcAttachmentCircuit struct {
+	// Name of the attachment circuit interface
+	Name string `json:"name"`
+}
+
+// Netconf is /network-topology:network-topology/topology/node
+//
+// The list of network nodes defined for the topology.
+type Netconf struct {
+	// The identifier of a node in the topology.
+	NodeId string `json:"node-id"`
+	// The username of the device.
+	Username string `json:"netconf-node-topology:username,omitempty"`
+	// The password of the user.
+	Password string `json:"netconf-node-topology:password,omitempty"`
+	// Username and password, the controller encrypts the password.
+	LoginPassword *NetconfLoginPassword `json:"netconf-node-topology:login-password,omitempty"`
+	// Username and password, the password is kept as it is.
+	LoginPasswordUnencrypted *NetconfLoginPasswordUnencrypted `json:"netconf-node-topology:login-password-unencrypted,omitempty"`
+	// Private key from the netconf-keystore.
+	KeyBased *NetconfKeyBased `json:"netconf-node-topology:key-based,omitempty"`
+	// The address or name of the device.
+	Host string `json:"netconf-node-topology:host,omitempty"`
+	// The netconf port of the device.
+	Port uint16 `json:"netconf-node-topology:port,omitempty" yang:"range=0..65535"`
+	// Connect over plain TCP instead of SSH.
+	TcpOnly *bool `json:"netconf-node-topology:tcp-only,omitempty"`
+	// Mount the device without its YANG modules.
+	Schemaless *bool `json:"netconf-node-topology:schemaless,omitempty"`
+	// Replaces or extends the capabilities advertised by the device.
+	YangModuleCapabilities *NetconfYangModuleCapabilities `json:"netconf-node-topology:yang-module-capabilities,omitempty"`
+	// If true, the connector would auto disconnect/reconnect when schemas are changed in the remote device.
+	ReconnectOnChangedSchema *bool `json:"netconf-node-topology:reconnect-on-changed-schema,omitempty"`
+	// Specifies timeout in milliseconds after which connection must be established.
+	ConnectionTimeoutMillis *uint32 `json:"netconf-node-topology:connection-timeout-millis,omitempty"`
+	// Timeout for blocking operations within transactions.
+	DefaultRequestTimeoutMillis *uint32 `json:"netconf-node-topology:default-request-timeout-millis,omitempty"`
+	// Maximum number of connection retries.
+	MaxConnectionAttempts *uint32 `json:"netconf-node-topology:max-connection-attempts,omitempty"`
+	// Initial timeout in milliseconds to wait between connection attempts.
+	BetweenAttemptsTimeoutMillis *uint16 `json:"netconf-node-topology:between-attempts-timeout-millis,omitempty"`
+	// Multiplier of the timeout between connection attempts.
+	SleepFactor *float64 `json:"netconf-node-topology:sleep-factor,omitempty"`
+	// Netconf connector sends keepalive RPCs while the session is idle, this delay specifies the delay between keepalive RPC in seconds.
+	KeepaliveDelay *uint32 `json:"netconf-node-topology:keepalive-delay,omitempty"`
+	// Limit of concurrent messages that can be send before reply messages are received.
+	ConcurrentRpcLimit *uint16 `json:"netconf-node-topology:concurrent-rpc-limit,omitempty"`
+}
+
+// NetconfLoginPassword is /network-topology:network-topology/topology/node/login-password
+//
+// Username and password, the controller encrypts the password.
+type NetconfLoginPassword struct {
+	// The username of the device.
+	Username string `json:"username,omitempty"`
+	// The password of the user.
+	Password string `json:"password,omitempty"`
+}
+
+// NetconfLoginPasswordUnencrypted is /network-topology:network-topology/topology/node/login-password-unencrypted
+//
+// Username and password, the password is kept as it is.
+type NetconfLoginPasswordUnencrypted struct {
+	// The username of the device.
+	Username string `json:"username,omitempty"`
+	// The password of the user.
+	Password string `json:"password,omitempty"`
+}
+
+// NetconfKeyBased is /network-topology:network-topology/topology/node/key-based
+//
+// Private key from the netconf-keystore.
+type NetconfKeyBased struct {
+	// The id of the private key in the netconf-keystore.
+	KeyId string `json:"key-id,omitempty"`
+	// The username of the device.
+	Username string `json:"username,omitempty"`
+}
+
+// NetconfYangModuleCapabilities is /network-topology:network-topology/topology/node/yang-module-capabilities
+//
+// Replaces or extends the capabilities advertised by the device.
+type NetconfYangModuleCapabilities struct {
+	// Whether to override or merge this list of capabilities with capabilities from device.
+	Override *bool `json:"override,omitempty"`
+	// Set a list of capabilities to override capabilities provided in device's hello message.
+	Capability []string `json:"capability,omitempty"`
+}
+
+// InterfaceModeEnum are the values of Interface-mode-enum of Cisco-IOS-XR-ifmgr-cfg
+type InterfaceModeEnum string
+
+// Values of InterfaceModeEnum
+const (
+	InterfaceModeEnumDefault      InterfaceModeEnum = "default"
+	InterfaceModeEnumPointToPoint InterfaceModeEnum = "point-to-point"
+	InterfaceModeEnumMultipoint   InterfaceModeEnum = "multipoint"
+	InterfaceModeEnumL2Transport  InterfaceModeEnum = "l2-transport"
+)
+
+// InterfaceModeEnumValues are the values of InterfaceModeEnum, ie for validation.StringInSlice
+var InterfaceModeEnumValues = []string{"default", "point-to-point", "multipoint", "l2-transport"}
+
+// Match are the values of Match of Cisco-IOS-XR-l2-eth-infra-datatypes
+type Match string
+
+// Values of Match
+const (
+	MatchMatchDefault       Match = "match-default"
+	MatchMatchUntagged      Match = "match-untagged"
+	MatchMatchDot1q         Match = "match-dot1q"
+	MatchMatchDot1ad        Match = "match-dot1ad"
+	MatchMatchDot1qPriority Match = "match-dot1q-priority"
+)
+
+// MatchValues are the values of Match, ie for validation.StringInSlice
+var MatchValues = []string{"match-default", "match-untagged", "match-dot1q", "match-dot1ad", "match-dot1q-priority"}
+
+// Rewrite are the values of Rewrite of Cisco-IOS-XR-l2-eth-infra-datatypes
+type Rewrite string
+
+// Values of Rewrite
+const (
+	RewritePop1          Rewrite = "pop1"
+	RewritePop2          Rewrite = "pop2"
+	RewritePush1         Rewrite = "push1"
+	RewritePush2         Rewrite = "push2"
+	RewriteTranslate1to1 Rewrite = "translate1to1"
+	RewriteTranslate1to2 Rewrite = "translate1to2"
+	RewriteTranslate2to1 Rewrite = "translate2to1"
+	RewriteTranslate2to2 Rewrite = "translate2to2"
+)
+
+// RewriteValues are the values of Rewrite, ie for validation.StringInSlice
+var RewriteValues = []string{"pop1", "pop2", "push1", "push2", "translate1to1", "translate1to2", "translate2to1", "translate2to2"}
+
+// yangModuleNamespaces are the XML namespaces of the modules the types are generated from
+var yangModuleNamespaces = map[string]string{
+	"Cisco-IOS-XR-ifmgr-cfg":        "http://cisco.com/ns/yang/Cisco-IOS-XR-ifmgr-cfg",
+	"Cisco-IOS-XR-infra-rsi-cfg":    "http://cisco.com/ns/yang/Cisco-IOS-XR-infra-rsi-cfg",
+	"Cisco-IOS-XR-l2-eth-infra-cfg": "http://cisco.com/ns/yang/Cisco-IOS-XR-l2-eth-infra-cfg",
+	"Cisco-IOS-XR-l2vpn-cfg":        "http://cisco.com/ns/yang/Cisco-IOS-XR-l2vpn-cfg",
+	"Cisco-IOS-XR-qos-ma-cfg":       "http://cisco.com/ns/yang/Cisco-IOS-XR-qos-ma-cfg",
+	"netconf-node-topology":         "urn:opendaylight:netconf-node-topology",
+	"network-topology":              "urn:TBD:params:xml:ns:yang:network-topology",
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// yangModule is a YANG module a resource configures and the earliest revision it was written against
+type yangModule struct {
+	Name     string
+	Revision string
+}
+
+func (y yangModule) String() string {
+	return fmt.Sprintf("%s (revision %s or later)", y.Name, y.Revision)
+}
+
+// modules returns the same YANG modules regardless of the resource's attributes
+func modules(required ...yangModule) func(d resourceGetter) []yangModule {
+	return func(d resourceGetter) []yangModule {
+		return required
+	}
+}
+
+// addressFamilyModules returns the ipv4 or ipv6 module depending on the address_family attribute
+func addressFamilyModules(ipv4 yangModule, ipv6 yangModule) func(d resourceGetter) []yangModule {
+	return func(d resourceGetter) []yangModule {
+		if d.Get("address_family").(string) == "ipv6" {
+			return []yangModule{ipv6}
+		}
+		return []yangModule{ipv4}
+	}
+}
+
+// resourceModules holds the YANG modules each resource on a mounted device needs the device to advertise
+var resourceModules = map[string]func(d resourceGetter) []yangModule{
+	"lsc_cisco_interface": modules(
+		yangModule{"Cisco-IOS-XR-ifmgr-cfg", "2015-07-30"},
+	),
+	"lsc_cisco_vlan": modules(
+		yangModule{"Cisco-IOS-XR-ifmgr-cfg", "2015-07-30"},
+		yangModule{"Cisco-IOS-XR-l2-eth-infra-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_l2vpn": modules(
+		yangModule{"Cisco-IOS-XR-l2vpn-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_vrf": modules(
+		yangModule{"Cisco-IOS-XR-infra-rsi-cfg", "2015-07-30"},
+	),
+	"lsc_cisco_bgp_neighbor": modules(
+		yangModule{"Cisco-IOS-XR-ipv4-bgp-cfg", "2015-08-27"},
+	),
+	"lsc_cisco_static_route": modules(
+		yangModule{"Cisco-IOS-XR-ip-static-cfg", "2015-09-10"},
+	),
+	"lsc_cisco_isis_interface": modules(
+		yangModule{"Cisco-IOS-XR-clns-isis-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_ospf_interface": modules(
+		yangModule{"Cisco-IOS-XR-ipv4-ospf-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_acl": addressFamilyModules(
+		yangModule{"Cisco-IOS-XR-ipv4-acl-cfg", "2015-11-09"},
+		yangModule{"Cisco-IOS-XR-ipv6-acl-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_acl_attachment": modules(
+		yangModule{"Cisco-IOS-XR-ip-pfilter-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_class_map": modules(
+		yangModule{"Cisco-IOS-XR-infra-policymgr-cfg", "2015-05-18"},
+	),
+	"lsc_cisco_policy_map": modules(
+		yangModule{"Cisco-IOS-XR-infra-policymgr-cfg", "2015-05-18"},
+	),
+	"lsc_cisco_route_policy": modules(
+		yangModule{"Cisco-IOS-XR-policy-repository-cfg", "2015-08-27"},
+	),
+	"lsc_cisco_prefix_set": modules(
+		yangModule{"Cisco-IOS-XR-policy-repository-cfg", "2015-08-27"},
+	),
+	"lsc_cisco_ntp_server": modules(
+		yangModule{"Cisco-IOS-XR-ip-ntp-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_logging_host": modules(
+		yangModule{"Cisco-IOS-XR-infra-syslog-cfg", "2016-06-22"},
+	),
+	"lsc_cisco_snmp_community": modules(
+		yangModule{"Cisco-IOS-XR-snmp-agent-cfg", "2015-10-27"},
+	),
+	"lsc_cisco_snmp_trap_host": modules(
+		yangModule{"Cisco-IOS-XR-snmp-agent-cfg", "2015-10-27"},
+	),
+	"lsc_cisco_name_server": modules(
+		yangModule{"Cisco-IOS-XR-ip-domain-cfg", "2015-05-13"},
+	),
+	"lsc_cisco_local_user": modules(
+		yangModule{"Cisco-IOS-XR-aaa-lib-cfg", "2015-11-09"},
+		yangModule{"Cisco-IOS-XR-aaa-locald-cfg", "2015-11-09"},
+	),
+	"lsc_cisco_aaa_server_group": func(d resourceGetter) []yangModule {
+		if d.Get("protocol").(string) == "radius" {
+			return []yangModule{{"Cisco-IOS-XR-aaa-lib-cfg", "2015-11-09"}, {"Cisco-IOS-XR-aaa-protocol-radius-cfg", "2015-11-09"}}
+		}
+		return []yangModule{{"Cisco-IOS-XR-aaa-lib-cfg", "2015-11-09"}, {"Cisco-IOS-XR-aaa-tacacs-cfg", "2015-11-09"}}
+	},
+}
+
+// checkYangModules returns an error naming the YANG modules the device is missing, devices
+// that are not mounted or connected yet are not checked
+func checkYangModules(apiClient *client.Client, name string, device string, required []yangModule) error {
+	advertised, err := apiClient.YangModules(device)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) || errors.Is(err, client.ErrNotConnected) {
+			log.Printf("[DEBUG] Skipping YANG module check of %s: %v", device, err)
+			return nil
+		}
+		return err
+	}
+
+	missing := []string{}
+	for _, module := range required {
+		if found, ok := advertised[module.Name]; !ok || found.Revision < module.Revision {
+			missing = append(missing, module.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s needs device %s to advertise YANG module %s", name, device, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// requireYangModules checks a resource's YANG modules against its device when planning and
+// before it is created or updated
+func requireYangModules(name string, r *schema.Resource, required func(d resourceGetter) []yangModule) {
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(d *schema.ResourceDiff, m interface{}) error {
+		if d.NewValueKnown("device") {
+			if err := checkYangModules(m.(*client.Client), name, d.Get("device").(string), required(d)); err != nil {
+				return err
+			}
+		}
+		if customizeDiff != nil {
+			return customizeDiff(d, m)
+		}
+		return nil
+	}
+
+	create := r.Create
+	r.Create = func(d *schema.ResourceData, m interface{}) error {
+		if err := checkYangModules(m.(*client.Client), name, d.Get("device").(string), required(d)); err != nil {
+			return err
+		}
+		return create(d, m)
+	}
+
+	if update := r.Update; update != nil {
+		r.Update = func(d *schema.ResourceData, m interface{}) error {
+			if err := checkYangModules(m.(*client.Client), name, d.Get("device").(string), required(d)); err != nil {
+				return err
+			}
+			return update(d, m)
+		}
+	}
+}
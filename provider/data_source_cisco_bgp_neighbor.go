@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCiscoBgpNeighbor() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"neighbor_address": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Address of the neighbor",
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device the neighbor is configured on",
+			},
+			"remote_as": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "AS of the neighbor",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of neighbor",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Session state, ie bgp-st-estab",
+			},
+			"established": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the session is established",
+			},
+			"established_time": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds since the session was established",
+			},
+			"messages_received": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"messages_sent": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"address_family": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Per address family prefix counters",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"prefixes_accepted": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"prefixes_advertised": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Read: dataSourceReadCiscoBgpNeighbor,
+	}
+}
+
+func dataSourceReadCiscoBgpNeighbor(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoBgpNeighborURLOperational(d.Get("device").(string), d.Get("neighbor_address").(string))
+
+	bodyBytes, err := apiClient.GetNetconf(url)
+	if err != nil {
+		return err
+	}
+
+	neighbor, err := payload.ParseNetconfCiscoBgpNeighborOperationalPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(neighbor.NeighborAddress)
+	d.Set("remote_as", neighbor.RemoteAs)
+	d.Set("description", neighbor.Description)
+	d.Set("state", neighbor.ConnectionState)
+	d.Set("established", neighbor.ConnectionState == "bgp-st-estab")
+	d.Set("established_time", neighbor.ConnectionEstablishedTime)
+	d.Set("messages_received", neighbor.MessagesReceived)
+	d.Set("messages_sent", neighbor.MessagesSent)
+
+	afs := []interface{}{}
+	for _, af := range neighbor.AfData {
+		afs = append(afs, map[string]interface{}{
+			"name":                af.AfName,
+			"prefixes_accepted":   af.PrefixesAccepted,
+			"prefixes_advertised": af.PrefixesAdvertised,
+		})
+	}
+	d.Set("address_family", afs)
+	return nil
+}
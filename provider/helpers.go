@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// expandStringSet converts a set of strings from the schema into a sorted slice
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+	return values
+}
+
+// writeNetconf creates the configuration of a new resource so configuration that is already on the
+// device is never silently taken over, the configuration of an existing resource is replaced if it
+// wasn't changed since it was last read
+func writeNetconf(d *schema.ResourceData, apiClient *client.Client, url string, payloadBody bytes.Buffer) error {
+	if !d.IsNewResource() {
+		return changedOutsideError(url, apiClient.PutNetconfIfUnchanged(url, payloadBody, stateVersion(d, url)))
+	}
+
+	err := apiClient.PostNetconf(url, payloadBody)
+	if errors.Is(err, client.ErrAlreadyExists) {
+		return fmt.Errorf("%s is already configured outside of terraform, import it instead: %w", url, err)
+	}
+	return err
+}
+
+// readNetconf gets the configuration of a resource and keeps its version in the versions attribute
+// of the resource, so later writes fail when someone else changed it in between. Only the fields
+// are read when there are any, ie the leaves the resource manages
+func readNetconf(d *schema.ResourceData, apiClient *client.Client, url string, fields ...string) ([]byte, error) {
+	bodyBytes, version, err := apiClient.GetNetconfVersion(url, client.RequestOptions{Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+	setStateVersion(d, url, version)
+	return bodyBytes, nil
+}
+
+// deleteNetconf deletes the configuration of a resource if it wasn't changed since it was last read
+func deleteNetconf(d *schema.ResourceData, apiClient *client.Client, url string) error {
+	return changedOutsideError(url, apiClient.DeleteNetconfIfUnchanged(url, stateVersion(d, url)))
+}
+
+// changedOutsideError describes a failed precondition of a conditional write
+func changedOutsideError(url string, err error) error {
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return fmt.Errorf("%s was changed outside of terraform since it was last read, refresh and re-plan: %w", url, err)
+	}
+	return err
+}
+
+// optionalBool returns a bool attribute, or nil when it isn't set so the controller default applies
+func optionalBool(d *schema.ResourceData, key string) *bool {
+	if v, ok := d.GetOkExists(key); ok {
+		value := v.(bool)
+		return &value
+	}
+	return nil
+}
+
+// optionalUint32 returns an int attribute as a uint32 leaf, or nil when it isn't set so the controller default applies
+func optionalUint32(d *schema.ResourceData, key string) *uint32 {
+	if v, ok := d.GetOkExists(key); ok {
+		value := uint32(v.(int))
+		return &value
+	}
+	return nil
+}
+
+// optionalUint16 returns an int attribute as a uint16 leaf, or nil when it isn't set so the controller default applies
+func optionalUint16(d *schema.ResourceData, key string) *uint16 {
+	if v, ok := d.GetOkExists(key); ok {
+		value := uint16(v.(int))
+		return &value
+	}
+	return nil
+}
+
+// optionalFloat returns a float attribute, or nil when it isn't set so the controller default applies
+func optionalFloat(d *schema.ResourceData, key string) *float64 {
+	if v, ok := d.GetOkExists(key); ok {
+		value := v.(float64)
+		return &value
+	}
+	return nil
+}
+
+// validateYang validates an attribute with the range or length the model gives a field of a
+// generated payload struct, ie payload.InterfaceConfigurationMtusMtu{} and Mtu
+func validateYang(v interface{}, field string) schema.SchemaValidateFunc {
+	restriction, min, max := payload.YangRestriction(v, field)
+	if restriction == "length" {
+		return validation.StringLenBetween(min, max)
+	}
+	return validation.IntBetween(min, max)
+}
+
+// resourceGetter is implemented by schema.ResourceData and schema.ResourceDiff
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// passwordHashPrefix marks a password that is already hashed for state
+const passwordHashPrefix = "sha256:"
+
+// hashPassword returns the hash of a password kept in state instead of the plaintext
+func hashPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(password))
+	return passwordHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// hashPasswordState is the StateFunc of password attributes, values that are already
+// hashed are kept so it can also be applied to values read from state
+func hashPasswordState(v interface{}) string {
+	password, _ := v.(string)
+	if strings.HasPrefix(password, passwordHashPrefix) {
+		return password
+	}
+	return hashPassword(password)
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Updates of resources that don't keep a version are unconditional instead of failing on it
+func TestUpdateNetconfDevices(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	apiClient := controller.client()
+
+	controller.reply(payload.NetconfMountURLOperational("r1"),
+		`{"node":[{"node-id":"r1","netconf-node-topology:connection-status":"connected"}]}`)
+	controller.reply(payload.NetconfCallhomeDeviceURL("r2"),
+		`{"device":[{"unique-id":"r2","ssh-host-key":"AAAA","credentials":{"username":"admin","passwords":["secret"]}}]}`)
+	controller.reply(payload.NetconfMountURLOperational("r2"),
+		`{"node":[{"node-id":"r2","netconf-node-topology:connection-status":"connected"}]}`)
+
+	device := schema.TestResourceDataRaw(t, resourceNetconfDevice().Schema, map[string]interface{}{
+		"name":       "r1",
+		"ip_address": "192.0.2.1",
+		"port":       830,
+		"username":   "admin",
+		"password":   "secret",
+	})
+	device.SetId("r1")
+	if err := resourceNetconfDevice().Update(device, apiClient); err != nil {
+		t.Fatalf("updating lsc_netconf_device: %s", err)
+	}
+
+	callhome := schema.TestResourceDataRaw(t, resourceNetconfCallhomeDevice().Schema, map[string]interface{}{
+		"unique_id":    "r2",
+		"ssh_host_key": "AAAA",
+		"username":     "admin",
+		"password":     "secret",
+	})
+	callhome.SetId("r2")
+	if err := resourceNetconfCallhomeDevice().Update(callhome, apiClient); err != nil {
+		t.Fatalf("updating lsc_netconf_callhome_device: %s", err)
+	}
+
+	expected := []string{
+		"PUT " + payload.NetconfMountURL("r1"),
+		"PUT " + payload.NetconfCallhomeDeviceURL("r2"),
+	}
+	if writes := controller.writes(); !reflect.DeepEqual(writes, expected) {
+		t.Errorf("expected writes %v, got %v", expected, writes)
+	}
+}
+
+// Merge updates are sent with the version each interface was last read with, interfaces with
+// the same name on different devices keep their own versions
+func TestUpdateCiscoInterfaceIfUnchanged(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	apiClient := controller.client()
+
+	r := Provider().(*schema.Provider).ResourcesMap["lsc_cisco_interface"]
+	for i, device := range []string{"r1", "r2"} {
+		url := payload.NetconfCiscoInterfaceURL(device, "GigabitEthernet0/0/0/1.100")
+		etag := fmt.Sprintf(`"%d"`, i+5)
+		controller.reply(url, `{"interface-configuration":[{"active":"act","interface-name":"GigabitEthernet0/0/0/1.100","description":"uplink"}]}`)
+		controller.headers[url] = http.Header{"Etag": []string{etag}}
+
+		state, err := r.Refresh(&terraform.InstanceState{ID: "GigabitEthernet0/0/0/1.100", Attributes: map[string]string{
+			"id":          "GigabitEthernet0/0/0/1.100",
+			"name":        "GigabitEthernet0/0/0/1.100",
+			"description": "uplink",
+			"device":      device,
+		}}, apiClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version := state.Attributes["versions."+url]; version != etag {
+			t.Fatalf("expected the refresh to keep the version %s of %s, got %v", etag, url, state.Attributes)
+		}
+
+		diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":        "GigabitEthernet0/0/0/1.100",
+			"description": "downlink",
+			"device":      device,
+		}), apiClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attr, ok := diff.Attributes["versions.%"]; !ok || !attr.NewComputed {
+			t.Errorf("expected the versions to be planned as unknown, got %v", diff.Attributes)
+		}
+		if _, err := r.Apply(state, diff, apiClient); err != nil {
+			t.Fatal(err)
+		}
+
+		if patch := controller.request("PATCH", url); patch == nil || patch.header.Get("If-Match") != etag {
+			t.Fatalf("expected the patch to be sent to %s with If-Match %s, got %v", url, etag, controller.writes())
+		}
+	}
+}
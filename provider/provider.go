@@ -2,14 +2,17 @@ package provider
 
 import (
 	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
 // Provider is the main terraform object
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"address": {
 				Type:        schema.TypeString,
@@ -26,20 +29,95 @@ func Provider() terraform.ResourceProvider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("SERVICE_TOKEN", ""),
 			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum requests in flight to the controller, 0 is unlimited",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_per_device": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Maximum writes in flight to each mounted device, 0 is unlimited, Default is 1 to avoid commit lock errors",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"batch_window_millis": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Hold writes to a mounted device for this long and commit the writes to it that arrive meanwhile as one YANG-Patch, 0 disables batching. Resources that depend on each other never share a batch",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"encoding": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "json",
+				Description:  "Encoding of the payloads sent to the controller, json or xml",
+				ValidateFunc: validation.StringInSlice([]string{"json", "xml"}, false),
+			},
+			"cache_reads": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read the interface-configurations and l2vpn database of a device once and serve the interfaces, vlans and l2vpns on it from them until the device is written to",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"lsc_netconf_device":  resourceNetconfDevice(),
-			"lsc_cisco_interface": resourceCiscoInterface(),
-			"lsc_cisco_vlan":      resourceCiscoVlan(),
-			"lsc_cisco_l2vpn":     resourceCiscoL2VPN(),
+			"lsc_netconf_device":          resourceNetconfDevice(),
+			"lsc_keystore_key":            resourceKeystoreKey(),
+			"lsc_netconf_callhome_device": resourceNetconfCallhomeDevice(),
+			"lsc_cisco_interface":         resourceCiscoInterface(),
+			"lsc_cisco_vlan":              resourceCiscoVlan(),
+			"lsc_cisco_l2vpn":             resourceCiscoL2VPN(),
+			"lsc_cisco_vrf":               resourceCiscoVrf(),
+			"lsc_cisco_bgp_neighbor":      resourceCiscoBgpNeighbor(),
+			"lsc_cisco_static_route":      resourceCiscoStaticRoute(),
+			"lsc_cisco_isis_interface":    resourceCiscoIsisInterface(),
+			"lsc_cisco_ospf_interface":    resourceCiscoOspfInterface(),
+			"lsc_cisco_acl":               resourceCiscoACL(),
+			"lsc_cisco_acl_attachment":    resourceCiscoACLAttachment(),
+			"lsc_cisco_class_map":         resourceCiscoClassMap(),
+			"lsc_cisco_policy_map":        resourceCiscoPolicyMap(),
+			"lsc_cisco_route_policy":      resourceCiscoRoutePolicy(),
+			"lsc_cisco_prefix_set":        resourceCiscoPrefixSet(),
+			"lsc_cisco_ntp_server":        resourceCiscoNtpServer(),
+			"lsc_cisco_logging_host":      resourceCiscoLoggingHost(),
+			"lsc_cisco_snmp_community":    resourceCiscoSnmpCommunity(),
+			"lsc_cisco_snmp_trap_host":    resourceCiscoSnmpTrapHost(),
+			"lsc_cisco_name_server":       resourceCiscoNameServer(),
+			"lsc_cisco_local_user":        resourceCiscoLocalUser(),
+			"lsc_cisco_aaa_server_group":  resourceCiscoAaaServerGroup(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"lsc_cisco_bgp_neighbor": dataSourceCiscoBgpNeighbor(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
+
+	for name, required := range resourceModules {
+		requireYangModules(name, provider.ResourcesMap[name], required)
+	}
+	for _, r := range provider.ResourcesMap {
+		trackVersions(r)
+	}
+	return provider
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	address := d.Get("address").(string)
 	port := d.Get("port").(int)
 	token := d.Get("token").(string)
-	return client.NewClient(address, port, token), nil
+	encoding := payload.JSON
+	if d.Get("encoding").(string) == "xml" {
+		encoding = payload.XML
+	}
+	return client.NewClient(address, port, token,
+		client.WithMaxConcurrentRequests(d.Get("max_concurrent_requests").(int)),
+		client.WithMaxConcurrentPerDevice(d.Get("max_concurrent_per_device").(int)),
+		client.WithBatchWindow(time.Duration(d.Get("batch_window_millis").(int))*time.Millisecond),
+		client.WithReadCache(d.Get("cache_reads").(bool)),
+		client.WithEncoding(encoding),
+	), nil
 }
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestProvider(t *testing.T) {
+	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testController is a fake controller that replies to GETs with the body kept for their path
+// and accepts every write, the requests it gets are recorded to check what the provider sent
+type testController struct {
+	server *httptest.Server
+
+	mutex    sync.Mutex
+	bodies   map[string]string      // replies to GETs by path
+	headers  map[string]http.Header // headers of the replies by path
+	status   map[string]int         // status of the writes to a path, 204 when there is none
+	requests []testRequest
+}
+
+// testRequest is a request the fake controller got
+type testRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+// newTestController starts a fake controller, it has to be closed
+func newTestController() *testController {
+	c := &testController{
+		bodies:  map[string]string{},
+		headers: map[string]http.Header{},
+		status:  map[string]int{},
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(c.serveHTTP))
+	return c
+}
+
+func (c *testController) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/")
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.requests = append(c.requests, testRequest{method: r.Method, path: path, header: r.Header, body: string(body)})
+
+	for key, values := range c.headers[strings.TrimPrefix(r.URL.EscapedPath(), "/")] {
+		w.Header()[key] = values
+	}
+	if r.Method != "GET" {
+		status, ok := c.status[path]
+		if !ok {
+			status = http.StatusNoContent
+		}
+		w.WriteHeader(status)
+		return
+	}
+	// Replies kept for a path are also the replies to the path with any query
+	reply, ok := c.bodies[path]
+	if !ok {
+		reply, ok = c.bodies[strings.TrimPrefix(r.URL.EscapedPath(), "/")]
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write([]byte(reply))
+}
+
+func (c *testController) close() {
+	c.server.Close()
+}
+
+// reply sets the body of the replies to GETs of a path
+func (c *testController) reply(path string, body string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.bodies[path] = body
+}
+
+// request returns the last request with a method and path, nil when there is none
+func (c *testController) request(method string, path string) *testRequest {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := len(c.requests) - 1; i >= 0; i-- {
+		if c.requests[i].method == method && c.requests[i].path == path {
+			return &c.requests[i]
+		}
+	}
+	return nil
+}
+
+// writes returns the requests other than GETs, by method and path
+func (c *testController) writes() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	writes := []string{}
+	for _, r := range c.requests {
+		if r.method != "GET" {
+			writes = append(writes, r.method+" "+r.path)
+		}
+	}
+	return writes
+}
+
+// client returns a client of the fake controller
+func (c *testController) client(opts ...client.Option) *client.Client {
+	i := strings.LastIndex(c.server.URL, ":")
+	port, _ := strconv.Atoi(c.server.URL[i+1:])
+	return client.NewClient(c.server.URL[:i], port, "token", opts...)
+}
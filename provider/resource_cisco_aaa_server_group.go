@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoAaaServerGroup() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the server-group",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this server-group",
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "tacacs or radius",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tacacs", "radius"}, false),
+			},
+			"server": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Servers of the group in the order they are tried",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "IPv4 address of a globally configured tacacs or radius host",
+							ValidateFunc: validation.SingleIP(),
+						},
+						"auth_port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Authentication port of a radius host",
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+						"acct_port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Accounting port of a radius host",
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+					},
+				},
+			},
+		},
+		Create: resourceCreateCiscoAaaServerGroup,
+		Read:   resourceReadCiscoAaaServerGroup,
+		Update: resourceCreateCiscoAaaServerGroup,
+		Delete: resourceDeleteCiscoAaaServerGroup,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoAaaServerGroup(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	protocol := d.Get("protocol").(string)
+
+	servers := []payload.AaaServerGroupServer{}
+	for _, v := range d.Get("server").([]interface{}) {
+		server := v.(map[string]interface{})
+		if protocol != "radius" && (server["auth_port"].(int) != 0 || server["acct_port"].(int) != 0) {
+			return fmt.Errorf("auth_port and acct_port only apply to radius server-groups, not %s", d.Get("name").(string))
+		}
+		servers = append(servers, payload.AaaServerGroupServer{
+			IPAddress:      server["address"].(string),
+			AuthPortNumber: server["auth_port"].(int),
+			AcctPortNumber: server["acct_port"].(int),
+		})
+	}
+
+	group := payload.NewCiscoAaaServerGroup(d.Get("name").(string), servers)
+
+	url := payload.NetconfCiscoAaaServerGroupURL(d.Get("device").(string), protocol, d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoAaaServerGroupPayload(protocol, group)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoAaaServerGroup(d, m))
+	})
+}
+
+func resourceReadCiscoAaaServerGroup(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoAaaServerGroupURL(d.Get("device").(string), d.Get("protocol").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	group, err := payload.ParseNetconfCiscoAaaServerGroupPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	servers := []payload.AaaServerGroupServer{}
+	if group.Servers != nil {
+		servers = group.Servers.Server
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].OrderingIndex < servers[j].OrderingIndex
+	})
+
+	serverList := []interface{}{}
+	for _, server := range servers {
+		serverList = append(serverList, map[string]interface{}{
+			"address":   server.IPAddress,
+			"auth_port": server.AuthPortNumber,
+			"acct_port": server.AcctPortNumber,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("protocol").(string), group.ServerGroupName))
+	d.Set("server", serverList)
+	return nil
+}
+
+func resourceDeleteCiscoAaaServerGroup(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoAaaServerGroupURL(d.Get("device").(string), d.Get("protocol").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,321 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoACL() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the access list resource",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this access list",
+			},
+			"address_family": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ipv4",
+				Description:  "Address family of the access list, ipv4 or ipv6",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ipv4", "ipv6"}, false),
+			},
+			"entry": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Access list entries in ascending sequence order",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sequence": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							Description:  "Sequence number of the entry",
+							ValidateFunc: validation.IntBetween(1, 2147483646),
+						},
+						"action": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "permit or deny",
+							ValidateFunc: validation.StringInSlice([]string{"permit", "deny"}, false),
+						},
+						"protocol": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "Protocol to match, ie tcp, udp or icmp, defaults to the address family",
+						},
+						"source_prefix": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "any",
+							Description:      "Source prefix in CIDR notation, an address or any",
+							ValidateFunc:     validateACLPrefix,
+							DiffSuppressFunc: suppressEquivalentACLPrefix,
+						},
+						"destination_prefix": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "any",
+							Description:      "Destination prefix in CIDR notation, an address or any",
+							ValidateFunc:     validateACLPrefix,
+							DiffSuppressFunc: suppressEquivalentACLPrefix,
+						},
+						"source_port": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Source port, port range or port with an operator, ie 80, 1024-65535, lt 1024, gt 1023 or neq 22",
+							ValidateFunc: validateACLPort,
+						},
+						"destination_port": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Destination port, port range or port with an operator, ie 80, 1024-65535, lt 1024, gt 1023 or neq 22",
+							ValidateFunc: validateACLPort,
+						},
+						"dscp": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "DSCP value to match, ie ef or af41",
+						},
+						"log": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Log matches against the entry",
+						},
+					},
+				},
+			},
+		},
+		Create: resourceCreateCiscoACL,
+		Read:   resourceReadCiscoACL,
+		Update: resourceCreateCiscoACL,
+		Delete: resourceDeleteCiscoACL,
+		CustomizeDiff: func(d *schema.ResourceDiff, m interface{}) error {
+			previous := 0
+			for _, v := range d.Get("entry").([]interface{}) {
+				sequence := v.(map[string]interface{})["sequence"].(int)
+				if sequence <= previous {
+					return fmt.Errorf("entry sequence %d must be greater than the previous entry sequence %d, entries are read back in sequence order", sequence, previous)
+				}
+				previous = sequence
+			}
+			return nil
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoACL(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	entries := []payload.AccessListEntry{}
+	for _, v := range d.Get("entry").([]interface{}) {
+		ace := v.(map[string]interface{})
+
+		entry := payload.AccessListEntry{
+			SequenceNumber: ace["sequence"].(int),
+			Grant:          ace["action"].(string),
+			Protocol:       ace["protocol"].(string),
+		}
+		if entry.Protocol == "" {
+			entry.Protocol = d.Get("address_family").(string)
+		}
+
+		source, err := payload.NewACLSourceNetwork(ace["source_prefix"].(string))
+		if err != nil {
+			return fmt.Errorf("entry %d: %s", entry.SequenceNumber, err)
+		}
+		entry.SourceNetwork = source
+
+		destination, err := payload.NewACLDestinationNetwork(ace["destination_prefix"].(string))
+		if err != nil {
+			return fmt.Errorf("entry %d: %s", entry.SequenceNumber, err)
+		}
+		entry.DestinationNetwork = destination
+
+		operator, first, second, err := payload.ParseACLPort(ace["source_port"].(string))
+		if err != nil {
+			return fmt.Errorf("entry %d: %s", entry.SequenceNumber, err)
+		}
+		if operator != "" {
+			entry.SourcePort = &payload.ACLSourcePort{
+				SourceOperator:   operator,
+				FirstSourcePort:  first,
+				SecondSourcePort: second,
+			}
+		}
+
+		operator, first, second, err = payload.ParseACLPort(ace["destination_port"].(string))
+		if err != nil {
+			return fmt.Errorf("entry %d: %s", entry.SequenceNumber, err)
+		}
+		if operator != "" {
+			entry.DestinationPort = &payload.ACLDestinationPort{
+				DestinationOperator:   operator,
+				FirstDestinationPort:  first,
+				SecondDestinationPort: second,
+			}
+		}
+
+		if dscp := ace["dscp"].(string); dscp != "" {
+			entry.Dscp = &payload.ACLDscp{
+				DscpOperator: "equal",
+				DscpMin:      dscp,
+			}
+		}
+		if ace["log"].(bool) {
+			entry.LogOption = "log"
+		}
+
+		entries = append(entries, entry)
+	}
+
+	acl := payload.CiscoACL{
+		AccessListName: d.Get("name").(string),
+		AccessListEntries: payload.AccessListEntries{
+			AccessListEntry: entries,
+		},
+	}
+
+	url := payload.NetconfCiscoACLURL(d.Get("device").(string), d.Get("address_family").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoACLPayload(acl)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoACL(d, m))
+	})
+}
+
+func resourceReadCiscoACL(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoACLURL(d.Get("device").(string), d.Get("address_family").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	acl, err := payload.ParseNetconfCiscoACLPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	aces := acl.AccessListEntries.AccessListEntry
+	sort.Slice(aces, func(i, j int) bool {
+		return aces[i].SequenceNumber < aces[j].SequenceNumber
+	})
+
+	entries := []interface{}{}
+	for _, ace := range aces {
+		entry := map[string]interface{}{
+			"sequence":           ace.SequenceNumber,
+			"action":             ace.Grant,
+			"protocol":           ace.Protocol,
+			"source_prefix":      ace.SourceNetwork.Prefix(),
+			"destination_prefix": ace.DestinationNetwork.Prefix(),
+			"source_port":        "",
+			"destination_port":   "",
+			"dscp":               "",
+			"log":                ace.LogOption == "log",
+		}
+		if ace.SourcePort != nil {
+			entry["source_port"], err = payload.FormatACLPort(ace.SourcePort.SourceOperator, ace.SourcePort.FirstSourcePort, ace.SourcePort.SecondSourcePort)
+			if err != nil {
+				return fmt.Errorf("entry %d: source port: %s", ace.SequenceNumber, err)
+			}
+		}
+		if ace.DestinationPort != nil {
+			entry["destination_port"], err = payload.FormatACLPort(ace.DestinationPort.DestinationOperator, ace.DestinationPort.FirstDestinationPort, ace.DestinationPort.SecondDestinationPort)
+			if err != nil {
+				return fmt.Errorf("entry %d: destination port: %s", ace.SequenceNumber, err)
+			}
+		}
+		if ace.Dscp != nil {
+			entry["dscp"] = ace.Dscp.DscpMin
+		}
+		entries = append(entries, entry)
+	}
+
+	d.SetId(acl.AccessListName)
+	d.Set("name", acl.AccessListName)
+	d.Set("entry", entries)
+	return nil
+}
+
+func resourceDeleteCiscoACL(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoACLURL(d.Get("device").(string), d.Get("address_family").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// validateACLPrefix rejects prefixes the device would read back differently, ie 10.1.1.5/24
+func validateACLPrefix(v interface{}, k string) ([]string, []error) {
+	if err := payload.ValidateACLPrefix(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// validateACLPort rejects ports the device doesn't take, ie 70000 or 2000-1000
+func validateACLPort(v interface{}, k string) ([]string, []error) {
+	if _, _, _, err := payload.ParseACLPort(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentACLPrefix ignores the difference between a prefix and the way the device
+// reads it back, ie between 10.1.1.5 and 10.1.1.5/32 or between ::/0 and any
+func suppressEquivalentACLPrefix(k, old, new string, d *schema.ResourceData) bool {
+	return payload.CanonicalACLPrefix(old) == payload.CanonicalACLPrefix(new)
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoACLAttachment() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"interface": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the interface to attach the access list to",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this interface",
+			},
+			"acl": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the access list, ie lsc_cisco_acl name",
+			},
+			"address_family": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ipv4",
+				Description:  "Address family of the access list, ipv4 or ipv6",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ipv4", "ipv6"}, false),
+			},
+			"direction": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Direction to filter, ingress or egress",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ingress", "egress"}, false),
+			},
+		},
+		Create: resourceCreateCiscoACLAttachment,
+		Read:   resourceReadCiscoACLAttachment,
+		Update: resourceCreateCiscoACLAttachment,
+		Delete: resourceDeleteCiscoACLAttachment,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+// packetFilterDirection maps the direction attribute to the packet filter container
+func packetFilterDirection(d *schema.ResourceData) string {
+	if d.Get("direction").(string) == "egress" {
+		return "outbound"
+	}
+	return "inbound"
+}
+
+func resourceCreateCiscoACLAttachment(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	attachment := payload.CiscoACLAttachment{
+		Name: d.Get("acl").(string),
+	}
+
+	url := payload.NetconfCiscoACLAttachmentURL(d.Get("device").(string), d.Get("interface").(string), d.Get("address_family").(string), packetFilterDirection(d))
+
+	payloadBody, err := payload.NetconfCiscoACLAttachmentPayload(packetFilterDirection(d), attachment)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoACLAttachment(d, m))
+	})
+}
+
+func resourceReadCiscoACLAttachment(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoACLAttachmentURL(d.Get("device").(string), d.Get("interface").(string), d.Get("address_family").(string), packetFilterDirection(d))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	attachment, err := payload.ParseNetconfCiscoACLAttachmentPayload(packetFilterDirection(d), bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("interface").(string), d.Get("direction").(string)))
+	d.Set("acl", attachment.Name)
+	return nil
+}
+
+func resourceDeleteCiscoACLAttachment(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoACLAttachmentURL(d.Get("device").(string), d.Get("interface").(string), d.Get("address_family").(string), packetFilterDirection(d))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoBgpNeighbor() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"neighbor_address": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Address of the neighbor, also acts as it's unique ID",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this neighbor",
+			},
+			"bgp_as": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "AS of the local BGP instance",
+				ForceNew:    true,
+			},
+			"remote_as": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "AS of the neighbor",
+			},
+			"update_source": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Interface to source the session from, ie Loopback0",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of neighbor",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "MD5 password for the session",
+			},
+			"bfd_fast_detect": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Enable BFD fast detection for the session",
+			},
+			"bfd_minimum_interval": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "BFD minimum interval in milliseconds",
+			},
+			"bfd_multiplier": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "BFD detection multiplier",
+			},
+			"keepalive_interval": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Keepalive timer in seconds, requires hold_time",
+			},
+			"hold_time": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Hold timer in seconds, requires keepalive_interval",
+			},
+			"address_family": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Address families activated for the neighbor",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "af-name, ie ipv4-unicast or vpnv4-unicast",
+							ValidateFunc: validation.StringInSlice([]string{"ipv4-unicast", "ipv6-unicast", "vpnv4-unicast", "vpnv6-unicast", "l2vpn-evpn"}, false),
+						},
+						"route_policy_in": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Route policy applied to received routes",
+						},
+						"route_policy_out": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Route policy applied to advertised routes",
+						},
+						"send_community": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Send community attributes to an eBGP neighbor",
+						},
+						"next_hop_self": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Set the next hop to this router when advertising",
+						},
+					},
+				},
+			},
+		},
+		Create: resourceCreateCiscoBgpNeighbor,
+		Read:   resourceReadCiscoBgpNeighbor,
+		Update: resourceCreateCiscoBgpNeighbor,
+		Delete: resourceDeleteCiscoBgpNeighbor,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoBgpNeighbor(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	neighbor := payload.CiscoBgpNeighbor{
+		NeighborAddress:       d.Get("neighbor_address").(string),
+		RemoteAs:              payload.NewBgpAs(d.Get("remote_as").(int)),
+		UpdateSourceInterface: d.Get("update_source").(string),
+		Description:           d.Get("description").(string),
+		BfdMinimumInterval:    d.Get("bfd_minimum_interval").(int),
+		BfdMultiplier:         d.Get("bfd_multiplier").(int),
+	}
+	if password := d.Get("password").(string); password != "" {
+		neighbor.Password = &payload.BgpPassword{
+			Password: password,
+		}
+	}
+	if d.Get("bfd_fast_detect").(bool) {
+		neighbor.BfdEnableModes = "default"
+	}
+	if d.Get("keepalive_interval").(int) != 0 || d.Get("hold_time").(int) != 0 {
+		neighbor.Timers = &payload.BgpTimers{
+			KeepaliveInterval: d.Get("keepalive_interval").(int),
+			HoldTime:          d.Get("hold_time").(int),
+		}
+	}
+
+	afs := []payload.BgpNeighborAf{}
+	for _, v := range d.Get("address_family").(*schema.Set).List() {
+		af := v.(map[string]interface{})
+		afs = append(afs, payload.BgpNeighborAf{
+			AfName:            af["name"].(string),
+			Activate:          &payload.Empty{},
+			RoutePolicyIn:     af["route_policy_in"].(string),
+			RoutePolicyOut:    af["route_policy_out"].(string),
+			SendCommunityEbgp: af["send_community"].(bool),
+			NextHopSelf:       af["next_hop_self"].(bool),
+		})
+	}
+	if len(afs) > 0 {
+		neighbor.NeighborAfs = &payload.BgpNeighborAfs{
+			NeighborAf: afs,
+		}
+	}
+
+	url := payload.NetconfCiscoBgpNeighborURL(d.Get("device").(string), d.Get("bgp_as").(int), d.Get("neighbor_address").(string))
+
+	payloadBody, err := payload.NetconfCiscoBgpNeighborPayload(neighbor)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoBgpNeighbor(d, m))
+	})
+}
+
+func resourceReadCiscoBgpNeighbor(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoBgpNeighborURL(d.Get("device").(string), d.Get("bgp_as").(int), d.Get("neighbor_address").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	neighbor, err := payload.ParseNetconfCiscoBgpNeighborPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	// The device only returns the encrypted password, so it is not read back
+	d.SetId(neighbor.NeighborAddress)
+	d.Set("neighbor_address", neighbor.NeighborAddress)
+	d.Set("remote_as", neighbor.RemoteAs.Int())
+	d.Set("update_source", neighbor.UpdateSourceInterface)
+	d.Set("description", neighbor.Description)
+	d.Set("bfd_fast_detect", neighbor.BfdEnableModes == "default")
+	d.Set("bfd_minimum_interval", neighbor.BfdMinimumInterval)
+	d.Set("bfd_multiplier", neighbor.BfdMultiplier)
+	if neighbor.Timers != nil {
+		d.Set("keepalive_interval", neighbor.Timers.KeepaliveInterval)
+		d.Set("hold_time", neighbor.Timers.HoldTime)
+	} else {
+		d.Set("keepalive_interval", 0)
+		d.Set("hold_time", 0)
+	}
+
+	afs := []interface{}{}
+	if neighbor.NeighborAfs != nil {
+		for _, af := range neighbor.NeighborAfs.NeighborAf {
+			afs = append(afs, map[string]interface{}{
+				"name":             af.AfName,
+				"route_policy_in":  af.RoutePolicyIn,
+				"route_policy_out": af.RoutePolicyOut,
+				"send_community":   af.SendCommunityEbgp,
+				"next_hop_self":    af.NextHopSelf,
+			})
+		}
+	}
+	d.Set("address_family", afs)
+	return nil
+}
+
+func resourceDeleteCiscoBgpNeighbor(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoBgpNeighborURL(d.Get("device").(string), d.Get("bgp_as").(int), d.Get("neighbor_address").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCiscoClassMap() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the class-map resource",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this class-map",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of class-map",
+			},
+			"match_any": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Match any of the criteria instead of all of them",
+			},
+			"match_dscp": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "DSCP values to match, ie ef or af41",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"match_precedence": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "IP precedence values to match, ie critical or 5",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"match_cos": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "802.1p CoS values to match",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+		Create: resourceCreateCiscoClassMap,
+		Read:   resourceReadCiscoClassMap,
+		Update: resourceCreateCiscoClassMap,
+		Delete: resourceDeleteCiscoClassMap,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoClassMap(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	match := payload.ClassMapMatch{}
+	for _, v := range d.Get("match_dscp").([]interface{}) {
+		match.Dscp = append(match.Dscp, v.(string))
+	}
+	for _, v := range d.Get("match_precedence").([]interface{}) {
+		match.Precedence = append(match.Precedence, v.(string))
+	}
+	for _, v := range d.Get("match_cos").([]interface{}) {
+		match.Cos = append(match.Cos, v.(int))
+	}
+
+	classMap := payload.CiscoClassMap{
+		Type:        "qos",
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Match:       &match,
+	}
+	if d.Get("match_any").(bool) {
+		classMap.ClassMapModeMatchAny = &payload.Empty{}
+	} else {
+		classMap.ClassMapModeMatchAll = &payload.Empty{}
+	}
+
+	url := payload.NetconfCiscoClassMapURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoClassMapPayload(classMap)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoClassMap(d, m))
+	})
+}
+
+func resourceReadCiscoClassMap(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoClassMapURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	classMap, err := payload.ParseNetconfCiscoClassMapPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(classMap.Name)
+	d.Set("name", classMap.Name)
+	d.Set("description", classMap.Description)
+	d.Set("match_any", classMap.ClassMapModeMatchAny != nil)
+	if classMap.Match != nil {
+		d.Set("match_dscp", classMap.Match.Dscp)
+		d.Set("match_precedence", classMap.Match.Precedence)
+		d.Set("match_cos", classMap.Match.Cos)
+	} else {
+		d.Set("match_dscp", nil)
+		d.Set("match_precedence", nil)
+		d.Set("match_cos", nil)
+	}
+	return nil
+}
+
+func resourceDeleteCiscoClassMap(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoClassMapURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
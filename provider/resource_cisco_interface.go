@@ -32,23 +32,31 @@ func resourceCiscoInterface() *schema.Resource {
 				Required:    true,
 				Description: "Device for this interface",
 			},
+			"vrf": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Vrf the interface is bound to, ie lsc_cisco_vrf name",
+				ValidateFunc: validateYang(payload.InterfaceConfiguration{}, "Vrf"),
+			},
 		},
 		Create: resourceCreateCiscoInterface,
 		Read:   resourceReadCiscoInterface,
-		Update: resourceCreateCiscoInterface,
+		Update: resourceUpdateCiscoInterface,
 		Delete: resourceDeleteCiscoInterface,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(45 * time.Second),
+			Update: schema.DefaultTimeout(45 * time.Second),
 		}}
 }
 
 func resourceCreateCiscoInterface(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*client.Client)
 
-	device := payload.CiscoInterface{
-		Name:        d.Get("name").(string),
-		Description: d.Get("description").(string),
-		Active:      "pre",
+	device := payload.InterfaceConfiguration{
+		InterfaceName: d.Get("name").(string),
+		Description:   d.Get("description").(string),
+		Vrf:           d.Get("vrf").(string),
+		Active:        "pre",
 	}
 
 	url := payload.NetconfCiscoInterfaceURL(d.Get("device").(string), d.Get("name").(string))
@@ -60,8 +68,11 @@ func resourceCreateCiscoInterface(d *schema.ResourceData, m interface{}) error {
 	}
 
 	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		err = apiClient.PutNetconf(url, payloadBody)
+		err = writeNetconf(d, apiClient, url, payloadBody)
 
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
 		if err != nil {
 			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
 		}
@@ -70,12 +81,65 @@ func resourceCreateCiscoInterface(d *schema.ResourceData, m interface{}) error {
 	})
 }
 
+// resourceUpdateCiscoInterface merges the changes into the interface-configuration so
+// leaves the provider doesn't manage are kept, a vrf or description that is no longer set is
+// removed as empty leaves are left out of the merge. The changes fail when the
+// interface-configuration was changed since it was last read
+func resourceUpdateCiscoInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	device := payload.InterfaceConfiguration{
+		InterfaceName: d.Get("name").(string),
+		Description:   d.Get("description").(string),
+		Vrf:           d.Get("vrf").(string),
+		Active:        "pre",
+	}
+
+	url := payload.NetconfCiscoInterfaceURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoInterfacePayload(device)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	patch := payload.NewYangPatch("lsc_cisco_interface " + device.InterfaceName)
+	if d.HasChange("vrf") && device.Vrf == "" {
+		patch.Remove("/Cisco-IOS-XR-infra-rsi-cfg:vrf")
+	}
+	if d.HasChange("description") && device.Description == "" {
+		patch.Remove("/description")
+	}
+	patch.Merge("", payloadBody)
+
+	return resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		_, err = apiClient.YangPatchNetconfIfUnchanged(url, patch, stateVersion(d, url))
+
+		if errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(changedOutsideError(url, err))
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoInterface(d, m))
+	})
+}
+
+// ciscoInterfaceFields are the leaves of the interface-configuration the resource reads
+var ciscoInterfaceFields = []string{
+	"active",
+	"interface-name",
+	"description",
+	"Cisco-IOS-XR-infra-rsi-cfg:vrf",
+}
+
 func resourceReadCiscoInterface(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*client.Client)
 
 	url := payload.NetconfCiscoInterfaceURL(d.Get("device").(string), d.Get("name").(string))
 
-	bodyBytes, err := apiClient.GetNetconf(url)
+	bodyBytes, err := readNetconf(d, apiClient, url, ciscoInterfaceFields...)
 	if err != nil {
 		if errors.Is(err, client.ErrNotFound) {
 			d.SetId("")
@@ -87,12 +151,13 @@ func resourceReadCiscoInterface(d *schema.ResourceData, m interface{}) error {
 	device, err := payload.ParseNetconfCiscoInterfacePayload(bodyBytes)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil
+		return err
 	}
 
-	d.SetId(device.Name)
-	d.Set("name", device.Name)
+	d.SetId(device.InterfaceName)
+	d.Set("name", device.InterfaceName)
 	d.Set("description", device.Description)
+	d.Set("vrf", device.Vrf)
 	return nil
 }
 
@@ -101,7 +166,10 @@ func resourceDeleteCiscoInterface(d *schema.ResourceData, m interface{}) error {
 
 	url := payload.NetconfCiscoInterfaceURL(d.Get("device").(string), d.Get("name").(string))
 
-	err := apiClient.DeleteNetconf(url)
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
 	if err != nil {
 		log.Print("[Error]: ", err)
 		return nil
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Empty descriptions are left out of the merge, so a cleared description is removed
+func TestUpdateCiscoInterfaceClearsDescription(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	apiClient := controller.client()
+
+	url := payload.NetconfCiscoInterfaceURL("r1", "GigabitEthernet0/0/0/1.100")
+	controller.reply(url, `{"interface-configuration":[{"active":"act","interface-name":"GigabitEthernet0/0/0/1.100","description":"uplink"}]}`)
+
+	r := Provider().(*schema.Provider).ResourcesMap["lsc_cisco_interface"]
+	state := &terraform.InstanceState{ID: "GigabitEthernet0/0/0/1.100", Attributes: map[string]string{
+		"id":          "GigabitEthernet0/0/0/1.100",
+		"name":        "GigabitEthernet0/0/0/1.100",
+		"description": "uplink",
+		"device":      "r1",
+	}}
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "GigabitEthernet0/0/0/1.100",
+		"description": "",
+		"device":      "r1",
+	}), apiClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, apiClient); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := controller.request("PATCH", url)
+	if patch == nil {
+		t.Fatalf("expected a patch of %s, got %v", url, controller.writes())
+	}
+	if !strings.Contains(patch.body, `"operation":"remove","target":"/description"`) {
+		t.Errorf("expected the description to be removed, got %s", patch.body)
+	}
+}
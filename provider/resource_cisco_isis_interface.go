@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoIsisInterface() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"interface": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the interface to add to IS-IS",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this interface",
+			},
+			"instance": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "IS-IS instance name",
+				ForceNew:    true,
+			},
+			"address_family": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ipv4",
+				Description:  "Unicast address family to run on the interface, ipv4 or ipv6",
+				ValidateFunc: validation.StringInSlice([]string{"ipv4", "ipv6"}, false),
+			},
+			"metric": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Metric for both levels",
+			},
+			"point_to_point": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Treat the interface as a point-to-point link",
+			},
+			"passive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Advertise the interface prefix without forming adjacencies",
+			},
+			"bfd_fast_detect": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Enable BFD fast detection for the address family",
+			},
+			"bfd_minimum_interval": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "BFD minimum interval in milliseconds",
+			},
+			"bfd_multiplier": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "BFD detection multiplier",
+			},
+		},
+		Create: resourceCreateCiscoIsisInterface,
+		Read:   resourceReadCiscoIsisInterface,
+		Update: resourceCreateCiscoIsisInterface,
+		Delete: resourceDeleteCiscoIsisInterface,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoIsisInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	afData := payload.IsisInterfaceAfData{
+		Running: &payload.Empty{},
+	}
+	if metric := d.Get("metric").(int); metric != 0 {
+		afData.Metrics = &payload.IsisMetrics{
+			Metric: []payload.IsisMetric{
+				{
+					Level:  "not-set",
+					Metric: metric,
+				},
+			},
+		}
+	}
+
+	intf := payload.CiscoIsisInterface{
+		InterfaceName: d.Get("interface").(string),
+		Running:       &payload.Empty{},
+		InterfaceAfs: &payload.IsisInterfaceAfs{
+			InterfaceAf: []payload.IsisInterfaceAf{
+				{
+					AfName:          d.Get("address_family").(string),
+					SafName:         "unicast",
+					InterfaceAfData: afData,
+				},
+			},
+		},
+	}
+	if d.Get("point_to_point").(bool) {
+		intf.PointToPoint = &payload.Empty{}
+	}
+	if d.Get("passive").(bool) {
+		intf.State = "passive"
+	}
+	if d.Get("bfd_fast_detect").(bool) {
+		intf.Bfd = &payload.IsisBfd{
+			EnableIpv4: d.Get("address_family").(string) == "ipv4",
+			EnableIpv6: d.Get("address_family").(string) == "ipv6",
+			Interval:   d.Get("bfd_minimum_interval").(int),
+			Multiplier: d.Get("bfd_multiplier").(int),
+		}
+	}
+
+	url := payload.NetconfCiscoIsisInterfaceURL(d.Get("device").(string), d.Get("instance").(string), d.Get("interface").(string))
+
+	payloadBody, err := payload.NetconfCiscoIsisInterfacePayload(intf)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoIsisInterface(d, m))
+	})
+}
+
+func resourceReadCiscoIsisInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoIsisInterfaceURL(d.Get("device").(string), d.Get("instance").(string), d.Get("interface").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	intf, err := payload.ParseNetconfCiscoIsisInterfacePayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(intf.InterfaceName)
+	d.Set("interface", intf.InterfaceName)
+	d.Set("point_to_point", intf.PointToPoint != nil)
+	d.Set("passive", intf.State == "passive")
+
+	metric := 0
+	if intf.InterfaceAfs != nil && len(intf.InterfaceAfs.InterfaceAf) > 0 {
+		af := intf.InterfaceAfs.InterfaceAf[0]
+		d.Set("address_family", af.AfName)
+		if af.InterfaceAfData.Metrics != nil && len(af.InterfaceAfData.Metrics.Metric) > 0 {
+			metric = af.InterfaceAfData.Metrics.Metric[0].Metric
+		}
+	}
+	d.Set("metric", metric)
+
+	if intf.Bfd != nil {
+		d.Set("bfd_fast_detect", intf.Bfd.EnableIpv4 || intf.Bfd.EnableIpv6)
+		d.Set("bfd_minimum_interval", intf.Bfd.Interval)
+		d.Set("bfd_multiplier", intf.Bfd.Multiplier)
+	} else {
+		d.Set("bfd_fast_detect", false)
+	}
+	return nil
+}
+
+func resourceDeleteCiscoIsisInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoIsisInterfaceURL(d.Get("device").(string), d.Get("instance").(string), d.Get("interface").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
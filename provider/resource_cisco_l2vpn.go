@@ -18,10 +18,11 @@ func resourceCiscoL2VPN() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"eviid": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "Eviid for l2vpn",
-				ForceNew:    true,
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "Eviid for l2vpn",
+				ForceNew:     true,
+				ValidateFunc: validateYang(payload.VlanAwareFlexibleXconnectService{}, "Eviid"),
 			},
 			"device": {
 				Type:        schema.TypeString,
@@ -51,7 +52,7 @@ func resourceCiscoL2VPN() *schema.Resource {
 func resourceCreateCiscoL2VPN(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*client.Client)
 
-	var Circuit = []payload.VlanAwareFxcAttachmentCircuit{
+	var Circuit = []payload.VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuitsVlanAwareFxcAttachmentCircuit{
 		{
 			Name: d.Get("interface_1").(string),
 		},
@@ -60,9 +61,9 @@ func resourceCreateCiscoL2VPN(d *schema.ResourceData, m interface{}) error {
 		},
 	}
 
-	device := payload.CiscoL2VPN{
-		Eviid: d.Get("eviid").(int),
-		VlanAwareFxcAttachmentCircuits: payload.VlanAwareFxcAttachmentCircuits{
+	device := payload.VlanAwareFlexibleXconnectService{
+		Eviid: uint32(d.Get("eviid").(int)),
+		VlanAwareFxcAttachmentCircuits: &payload.VlanAwareFlexibleXconnectServiceVlanAwareFxcAttachmentCircuits{
 			VlanAwareFxcAttachmentCircuit: Circuit,
 		},
 	}
@@ -76,8 +77,11 @@ func resourceCreateCiscoL2VPN(d *schema.ResourceData, m interface{}) error {
 	}
 
 	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		err = apiClient.PutNetconf(url, payloadBody)
+		err = writeNetconf(d, apiClient, url, payloadBody)
 
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
 		if err != nil {
 			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
 		}
@@ -91,7 +95,7 @@ func resourceReadCiscoL2VPN(d *schema.ResourceData, m interface{}) error {
 
 	url := payload.NetconfCiscoL2VPNURL(d.Get("device").(string), d.Get("eviid").(int))
 
-	bodyBytes, err := apiClient.GetNetconf(url)
+	bodyBytes, err := readNetconf(d, apiClient, url)
 	if err != nil {
 		if errors.Is(err, client.ErrNotFound) {
 			d.SetId("")
@@ -103,13 +107,23 @@ func resourceReadCiscoL2VPN(d *schema.ResourceData, m interface{}) error {
 	device, err := payload.ParseNetconfCiscoL2VPNPayload(bodyBytes)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil
+		return err
 	}
 
-	d.SetId(strconv.Itoa(device.Eviid))
-	d.Set("eviid", device.Eviid)
-	d.Set("interface_1", device.VlanAwareFxcAttachmentCircuits.VlanAwareFxcAttachmentCircuit[0].Name)
-	d.Set("interface_2", device.VlanAwareFxcAttachmentCircuits.VlanAwareFxcAttachmentCircuit[1].Name)
+	d.SetId(strconv.Itoa(int(device.Eviid)))
+	d.Set("eviid", int(device.Eviid))
+
+	// Circuits removed outside of terraform show as a change to the interfaces
+	interfaces := []string{"", ""}
+	if device.VlanAwareFxcAttachmentCircuits != nil {
+		for i, circuit := range device.VlanAwareFxcAttachmentCircuits.VlanAwareFxcAttachmentCircuit {
+			if i < len(interfaces) {
+				interfaces[i] = circuit.Name
+			}
+		}
+	}
+	d.Set("interface_1", interfaces[0])
+	d.Set("interface_2", interfaces[1])
 	return nil
 }
 
@@ -118,7 +132,10 @@ func resourceDeleteCiscoL2VPN(d *schema.ResourceData, m interface{}) error {
 
 	url := payload.NetconfCiscoL2VPNURL(d.Get("device").(string), d.Get("eviid").(int))
 
-	err := apiClient.DeleteNetconf(url)
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
 	if err != nil {
 		log.Print("[Error]: ", err)
 		return nil
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCiscoLocalUser() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The username, also acts as it's unique ID",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this user",
+			},
+			"groups": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Usergroups the user is a member of, ie root-lr, netadmin",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Plaintext secret of the user, only its md5crypt hash is sent to the device",
+			},
+		},
+		Create: resourceCreateCiscoLocalUser,
+		Read:   resourceReadCiscoLocalUser,
+		Update: resourceCreateCiscoLocalUser,
+		Delete: resourceDeleteCiscoLocalUser,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoLocalUser(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	secret, err := payload.NewMd5CryptSecret(d.Get("secret").(string))
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	user := payload.NewCiscoLocalUser(d.Get("name").(string), expandStringSet(d.Get("groups").(*schema.Set)), secret)
+
+	url := payload.NetconfCiscoLocalUserURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoLocalUserPayload(user)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoLocalUser(d, m))
+	})
+}
+
+func resourceReadCiscoLocalUser(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoLocalUserURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	user, err := payload.ParseNetconfCiscoLocalUserPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(user.Name)
+	d.Set("groups", user.Groups())
+
+	// The device only holds the hash, the secret is drifted when the plaintext in state
+	// no longer hashes to it with the device's salt. Other hash schemes can't be compared.
+	if user.Secret == "" || (strings.HasPrefix(user.Secret, "$1$") && !payload.Md5CryptMatches(d.Get("secret").(string), user.Secret)) {
+		d.Set("secret", "")
+	}
+	return nil
+}
+
+func resourceDeleteCiscoLocalUser(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoLocalUserURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
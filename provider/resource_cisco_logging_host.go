@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoLoggingHost() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "IPv4 or IPv6 address of the syslog server",
+				ForceNew:     true,
+				ValidateFunc: validation.SingleIP(),
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this logging host",
+			},
+			"vrf": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Vrf the syslog server is reached through",
+				ForceNew:    true,
+			},
+			"severity": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      6,
+				Description:  "Lowest severity sent to the host, 0 emergencies to 7 debugging, Default is 6",
+				ValidateFunc: validation.IntBetween(0, 7),
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      514,
+				Description:  "UDP port of the syslog server, Default is 514",
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+		},
+		Create: resourceCreateCiscoLoggingHost,
+		Read:   resourceReadCiscoLoggingHost,
+		Update: resourceCreateCiscoLoggingHost,
+		Delete: resourceDeleteCiscoLoggingHost,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoLoggingHost(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	host := payload.NewCiscoLoggingHost(d.Get("address").(string), payload.LoggingSeverityPort{
+		Severity: d.Get("severity").(int),
+		Port:     d.Get("port").(int),
+	})
+
+	url := payload.NetconfCiscoLoggingHostURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("address").(string))
+
+	payloadBody, err := payload.NetconfCiscoLoggingHostPayload(host)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoLoggingHost(d, m))
+	})
+}
+
+func resourceReadCiscoLoggingHost(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoLoggingHostURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("address").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	host, err := payload.ParseNetconfCiscoLoggingHostPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	severityPort := host.SeverityPort()
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("vrf").(string), host.Address))
+	d.Set("severity", severityPort.Severity)
+	d.Set("port", severityPort.Port)
+	return nil
+}
+
+func resourceDeleteCiscoLoggingHost(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoLoggingHostURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("address").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoNameServer() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "IPv4 or IPv6 address of the name server",
+				ForceNew:     true,
+				ValidateFunc: validation.SingleIP(),
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this name server",
+			},
+			"vrf": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Vrf the name server is reached through",
+				ForceNew:    true,
+			},
+			"order": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Order in which the name servers are queried, lowest first",
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+		Create: resourceCreateCiscoNameServer,
+		Read:   resourceReadCiscoNameServer,
+		Update: resourceCreateCiscoNameServer,
+		Delete: resourceDeleteCiscoNameServer,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoNameServer(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	server := payload.CiscoNameServer{
+		Order:         d.Get("order").(int),
+		ServerAddress: d.Get("address").(string),
+	}
+
+	url := payload.NetconfCiscoNameServerURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("order").(int), d.Get("address").(string))
+
+	payloadBody, err := payload.NetconfCiscoNameServerPayload(server)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoNameServer(d, m))
+	})
+}
+
+func resourceReadCiscoNameServer(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoNameServerURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("order").(int), d.Get("address").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	server, err := payload.ParseNetconfCiscoNameServerPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d/%s", d.Get("vrf").(string), server.Order, server.ServerAddress))
+	return nil
+}
+
+func resourceDeleteCiscoNameServer(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoNameServerURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("order").(int), d.Get("address").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
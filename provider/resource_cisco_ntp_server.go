@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoNtpServer() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "IPv4 or IPv6 address of the NTP server",
+				ForceNew:     true,
+				ValidateFunc: validation.SingleIP(),
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this NTP server",
+			},
+			"vrf": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Vrf the NTP server is reached through",
+				ForceNew:    true,
+			},
+			"prefer": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Prefer this server over the others",
+			},
+			"iburst": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Send a burst of packets when the server is unreachable",
+			},
+			"source_interface": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Interface to source NTP packets from, ie Loopback0",
+			},
+		},
+		Create: resourceCreateCiscoNtpServer,
+		Read:   resourceReadCiscoNtpServer,
+		Update: resourceCreateCiscoNtpServer,
+		Delete: resourceDeleteCiscoNtpServer,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoNtpServer(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	peerType := payload.NtpPeerType{
+		SourceInterface: d.Get("source_interface").(string),
+	}
+	if d.Get("prefer").(bool) {
+		peerType.Prefer = &payload.Empty{}
+	}
+	if d.Get("iburst").(bool) {
+		peerType.Iburst = &payload.Empty{}
+	}
+
+	server := payload.NewCiscoNtpServer(d.Get("address").(string), peerType)
+
+	url := payload.NetconfCiscoNtpServerURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("address").(string))
+
+	payloadBody, err := payload.NetconfCiscoNtpServerPayload(server)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoNtpServer(d, m))
+	})
+}
+
+func resourceReadCiscoNtpServer(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoNtpServerURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("address").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	server, err := payload.ParseNetconfCiscoNtpServerPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	peerType, ok := server.Server()
+	if !ok {
+		// The address is configured as a peer rather than a server
+		d.SetId("")
+		return nil
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("vrf").(string), d.Get("address").(string)))
+	d.Set("prefer", peerType.Prefer != nil)
+	d.Set("iburst", peerType.Iburst != nil)
+	d.Set("source_interface", peerType.SourceInterface)
+	return nil
+}
+
+func resourceDeleteCiscoNtpServer(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoNtpServerURL(d.Get("device").(string), d.Get("vrf").(string), d.Get("address").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCiscoOspfInterface() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"interface": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the interface to add to OSPF",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this interface",
+			},
+			"process": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "OSPF process name",
+				ForceNew:    true,
+			},
+			"area": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "OSPF area, either an integer or dotted decimal",
+				ForceNew:    true,
+			},
+			"cost": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Interface cost",
+			},
+			"point_to_point": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Use the point-to-point network type",
+			},
+			"passive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Advertise the interface prefix without forming adjacencies",
+			},
+			"bfd_fast_detect": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Enable BFD fast detection",
+			},
+			"bfd_minimum_interval": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "BFD minimum interval in milliseconds",
+			},
+			"bfd_multiplier": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "BFD detection multiplier",
+			},
+		},
+		Create: resourceCreateCiscoOspfInterface,
+		Read:   resourceReadCiscoOspfInterface,
+		Update: resourceCreateCiscoOspfInterface,
+		Delete: resourceDeleteCiscoOspfInterface,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoOspfInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	intf := payload.CiscoOspfInterface{
+		InterfaceName: d.Get("interface").(string),
+		Running:       &payload.Empty{},
+		Cost:          d.Get("cost").(int),
+		Passive:       d.Get("passive").(bool),
+	}
+	if d.Get("point_to_point").(bool) {
+		intf.NetworkType = "point-to-point"
+	}
+	if d.Get("bfd_fast_detect").(bool) {
+		intf.Bfd = &payload.OspfBfd{
+			FastDetectMode:      "default",
+			Interval:            d.Get("bfd_minimum_interval").(int),
+			DetectionMultiplier: d.Get("bfd_multiplier").(int),
+		}
+	}
+
+	url := payload.NetconfCiscoOspfInterfaceURL(d.Get("device").(string), d.Get("process").(string), d.Get("area").(string), d.Get("interface").(string))
+
+	payloadBody, err := payload.NetconfCiscoOspfInterfacePayload(intf)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoOspfInterface(d, m))
+	})
+}
+
+func resourceReadCiscoOspfInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoOspfInterfaceURL(d.Get("device").(string), d.Get("process").(string), d.Get("area").(string), d.Get("interface").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	intf, err := payload.ParseNetconfCiscoOspfInterfacePayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(intf.InterfaceName)
+	d.Set("interface", intf.InterfaceName)
+	d.Set("cost", intf.Cost)
+	d.Set("point_to_point", intf.NetworkType == "point-to-point")
+	d.Set("passive", intf.Passive)
+	if intf.Bfd != nil {
+		d.Set("bfd_fast_detect", intf.Bfd.FastDetectMode != "")
+		d.Set("bfd_minimum_interval", intf.Bfd.Interval)
+		d.Set("bfd_multiplier", intf.Bfd.DetectionMultiplier)
+	} else {
+		d.Set("bfd_fast_detect", false)
+	}
+	return nil
+}
+
+func resourceDeleteCiscoOspfInterface(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoOspfInterfaceURL(d.Get("device").(string), d.Get("process").(string), d.Get("area").(string), d.Get("interface").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoPolicyMap() *schema.Resource {
+	fmt.Print()
+	rateUnits := []string{"bps", "kbps", "mbps", "gbps", "percent"}
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the policy-map resource",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this policy-map",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of policy-map",
+			},
+			"class": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Classes of the policy-map in evaluation order, use class-default last",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the class-map, ie lsc_cisco_class_map name or class-default",
+						},
+						"police_rate": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Policing rate, traffic above it is dropped",
+						},
+						"police_rate_unit": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "kbps",
+							Description:  "Unit of police_rate",
+							ValidateFunc: validation.StringInSlice(rateUnits, false),
+						},
+						"police_burst": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Committed burst size",
+						},
+						"police_burst_unit": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "bytes",
+							Description:  "Unit of police_burst",
+							ValidateFunc: validation.StringInSlice([]string{"bytes", "kbytes", "mbytes", "us", "ms"}, false),
+						},
+						"shape_rate": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Shaping rate",
+						},
+						"shape_rate_unit": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "kbps",
+							Description:  "Unit of shape_rate",
+							ValidateFunc: validation.StringInSlice(rateUnits, false),
+						},
+						"set_dscp": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "DSCP value to mark, ie ef",
+						},
+						"set_cos": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "802.1p CoS value to mark",
+						},
+					},
+				},
+			},
+		},
+		Create: resourceCreateCiscoPolicyMap,
+		Read:   resourceReadCiscoPolicyMap,
+		Update: resourceCreateCiscoPolicyMap,
+		Delete: resourceDeleteCiscoPolicyMap,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoPolicyMap(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	rules := []payload.PolicyMapRule{}
+	for _, v := range d.Get("class").([]interface{}) {
+		class := v.(map[string]interface{})
+
+		rule := payload.PolicyMapRule{
+			ClassName: class["name"].(string),
+			ClassType: "qos",
+		}
+		if rate := class["police_rate"].(int); rate != 0 {
+			rule.Police = &payload.PolicyPolice{
+				Rate: payload.PolicyRate{
+					Value: rate,
+					Units: class["police_rate_unit"].(string),
+				},
+				ConformAction: &payload.PolicyAction{Transmit: &payload.Empty{}},
+				ExceedAction:  &payload.PolicyAction{Drop: &payload.Empty{}},
+			}
+			if burst := class["police_burst"].(int); burst != 0 {
+				rule.Police.Burst = &payload.PolicyRate{
+					Value: burst,
+					Units: class["police_burst_unit"].(string),
+				}
+			}
+		}
+		if rate := class["shape_rate"].(int); rate != 0 {
+			rule.Shape = &payload.PolicyShape{
+				Rate: payload.PolicyRate{
+					Value: rate,
+					Units: class["shape_rate_unit"].(string),
+				},
+			}
+		}
+		if class["set_dscp"].(string) != "" || class["set_cos"].(int) != 0 {
+			rule.Set = &payload.PolicySet{
+				Dscp: class["set_dscp"].(string),
+				Cos:  class["set_cos"].(int),
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	policyMap := payload.CiscoPolicyMap{
+		Type:          "qos",
+		Name:          d.Get("name").(string),
+		Description:   d.Get("description").(string),
+		PolicyMapRule: rules,
+	}
+
+	url := payload.NetconfCiscoPolicyMapURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoPolicyMapPayload(policyMap)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoPolicyMap(d, m))
+	})
+}
+
+func resourceReadCiscoPolicyMap(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoPolicyMapURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	policyMap, err := payload.ParseNetconfCiscoPolicyMapPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	classes := []interface{}{}
+	for _, rule := range policyMap.PolicyMapRule {
+		class := map[string]interface{}{
+			"name":              rule.ClassName,
+			"police_rate":       0,
+			"police_rate_unit":  "kbps",
+			"police_burst":      0,
+			"police_burst_unit": "bytes",
+			"shape_rate":        0,
+			"shape_rate_unit":   "kbps",
+			"set_dscp":          "",
+			"set_cos":           0,
+		}
+		if rule.Police != nil {
+			class["police_rate"] = rule.Police.Rate.Value
+			class["police_rate_unit"] = rule.Police.Rate.Units
+			if rule.Police.Burst != nil {
+				class["police_burst"] = rule.Police.Burst.Value
+				class["police_burst_unit"] = rule.Police.Burst.Units
+			}
+		}
+		if rule.Shape != nil {
+			class["shape_rate"] = rule.Shape.Rate.Value
+			class["shape_rate_unit"] = rule.Shape.Rate.Units
+		}
+		if rule.Set != nil {
+			class["set_dscp"] = rule.Set.Dscp
+			class["set_cos"] = rule.Set.Cos
+		}
+		classes = append(classes, class)
+	}
+
+	d.SetId(policyMap.Name)
+	d.Set("name", policyMap.Name)
+	d.Set("description", policyMap.Description)
+	d.Set("class", classes)
+	return nil
+}
+
+func resourceDeleteCiscoPolicyMap(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoPolicyMapURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCiscoPrefixSet() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the prefix-set resource",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this prefix-set",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Comma separated prefixes of the prefix-set, without the prefix-set and end-set lines",
+				StateFunc: func(v interface{}) string {
+					return payload.NormalizePrefixSet(v.(string))
+				},
+			},
+		},
+		Create: resourceCreateCiscoPrefixSet,
+		Read:   resourceReadCiscoPrefixSet,
+		Update: resourceCreateCiscoPrefixSet,
+		Delete: resourceDeleteCiscoPrefixSet,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoPrefixSet(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	set := payload.NewCiscoPrefixSet(d.Get("name").(string), d.Get("body").(string))
+
+	url := payload.NetconfCiscoPrefixSetURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoPrefixSetPayload(set)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoPrefixSet(d, m))
+	})
+}
+
+func resourceReadCiscoPrefixSet(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoPrefixSetURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	set, err := payload.ParseNetconfCiscoPrefixSetPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(set.SetName)
+	d.Set("name", set.SetName)
+	d.Set("body", set.Body())
+	return nil
+}
+
+func resourceDeleteCiscoPrefixSet(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoPrefixSetURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
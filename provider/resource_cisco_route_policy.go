@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCiscoRoutePolicy() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the route-policy resource",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this route-policy",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "RPL statements of the route-policy, without the route-policy and end-policy lines",
+				StateFunc: func(v interface{}) string {
+					return payload.NormalizeRPL(v.(string))
+				},
+			},
+		},
+		Create: resourceCreateCiscoRoutePolicy,
+		Read:   resourceReadCiscoRoutePolicy,
+		Update: resourceCreateCiscoRoutePolicy,
+		Delete: resourceDeleteCiscoRoutePolicy,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoRoutePolicy(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	policy := payload.NewCiscoRoutePolicy(d.Get("name").(string), d.Get("body").(string))
+
+	url := payload.NetconfCiscoRoutePolicyURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoRoutePolicyPayload(policy)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoRoutePolicy(d, m))
+	})
+}
+
+func resourceReadCiscoRoutePolicy(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoRoutePolicyURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	policy, err := payload.ParseNetconfCiscoRoutePolicyPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(policy.RoutePolicyName)
+	d.Set("name", policy.RoutePolicyName)
+	d.Set("body", policy.Body())
+	return nil
+}
+
+func resourceDeleteCiscoRoutePolicy(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoRoutePolicyURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
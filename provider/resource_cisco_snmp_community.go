@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoSnmpCommunity() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The community string, also acts as it's unique ID",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this community",
+			},
+			"access": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "read-only",
+				Description:  "read-only or read-write, Default is read-only",
+				ValidateFunc: validation.StringInSlice([]string{"read-only", "read-write"}, false),
+			},
+			"view": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "SNMP view to restrict the community to",
+			},
+			"acl": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "IPv4 access list of hosts allowed to use the community",
+			},
+		},
+		Create: resourceCreateCiscoSnmpCommunity,
+		Read:   resourceReadCiscoSnmpCommunity,
+		Update: resourceCreateCiscoSnmpCommunity,
+		Delete: resourceDeleteCiscoSnmpCommunity,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoSnmpCommunity(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	community := payload.CiscoSnmpCommunity{
+		CommunityName: d.Get("name").(string),
+		Priviledge:    d.Get("access").(string),
+		ViewName:      d.Get("view").(string),
+		V4AccessList:  d.Get("acl").(string),
+	}
+
+	url := payload.NetconfCiscoSnmpCommunityURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoSnmpCommunityPayload(community)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoSnmpCommunity(d, m))
+	})
+}
+
+func resourceReadCiscoSnmpCommunity(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoSnmpCommunityURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	community, err := payload.ParseNetconfCiscoSnmpCommunityPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(community.CommunityName)
+	d.Set("access", community.Priviledge)
+	d.Set("view", community.ViewName)
+	d.Set("acl", community.V4AccessList)
+	return nil
+}
+
+func resourceDeleteCiscoSnmpCommunity(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoSnmpCommunityURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
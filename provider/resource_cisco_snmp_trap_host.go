@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoSnmpTrapHost() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "IPv4 or IPv6 address of the trap receiver",
+				ForceNew:     true,
+				ValidateFunc: validation.SingleIP(),
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this trap host",
+			},
+			"community": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Community string sent with the traps",
+			},
+			"version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2c",
+				Description:  "SNMP version of the traps, 1 or 2c, Default is 2c",
+				ValidateFunc: validation.StringInSlice([]string{"1", "2c"}, false),
+			},
+		},
+		Create: resourceCreateCiscoSnmpTrapHost,
+		Read:   resourceReadCiscoSnmpTrapHost,
+		Update: resourceCreateCiscoSnmpTrapHost,
+		Delete: resourceDeleteCiscoSnmpTrapHost,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoSnmpTrapHost(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	host := payload.CiscoSnmpTrapHost{
+		IPAddress: d.Get("address").(string),
+		DefaultUserCommunities: payload.SnmpDefaultUserCommunities{
+			DefaultUserCommunity: []payload.SnmpDefaultUserCommunity{
+				{
+					CommunityName: d.Get("community").(string),
+					Version:       d.Get("version").(string),
+				},
+			},
+		},
+	}
+
+	url := payload.NetconfCiscoSnmpTrapHostURL(d.Get("device").(string), d.Get("address").(string))
+
+	payloadBody, err := payload.NetconfCiscoSnmpTrapHostPayload(host)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoSnmpTrapHost(d, m))
+	})
+}
+
+func resourceReadCiscoSnmpTrapHost(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoSnmpTrapHostURL(d.Get("device").(string), d.Get("address").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	host, err := payload.ParseNetconfCiscoSnmpTrapHostPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	communities := host.DefaultUserCommunities.DefaultUserCommunity
+	if len(communities) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	d.SetId(host.IPAddress)
+	d.Set("community", communities[0].CommunityName)
+	d.Set("version", communities[0].Version)
+	return nil
+}
+
+func resourceDeleteCiscoSnmpTrapHost(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoSnmpTrapHostURL(d.Get("device").(string), d.Get("address").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
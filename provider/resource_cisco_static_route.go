@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoStaticRoute() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this static route",
+			},
+			"vrf": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Vrf of the static route, default is the global table",
+				ForceNew:    true,
+			},
+			"address_family": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ipv4",
+				Description:  "Address family of the prefix, ipv4 or ipv6",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ipv4", "ipv6"}, false),
+			},
+			"prefix": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Destination prefix in CIDR notation",
+				ForceNew:     true,
+				ValidateFunc: validation.CIDRNetwork(0, 128),
+			},
+			"next_hop_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Next hop address, at least one of next_hop_address or next_hop_interface is required",
+				ForceNew:    true,
+			},
+			"next_hop_interface": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Next hop interface, at least one of next_hop_address or next_hop_interface is required",
+				ForceNew:    true,
+			},
+			"distance": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Administrative distance of the route",
+			},
+			"tag": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Tag for the route",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of static route",
+			},
+		},
+		Create: resourceCreateCiscoStaticRoute,
+		Read:   resourceReadCiscoStaticRoute,
+		Update: resourceCreateCiscoStaticRoute,
+		Delete: resourceDeleteCiscoStaticRoute,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+// staticRouteURL returns the next hop URL for the static route in the resource data
+func staticRouteURL(d *schema.ResourceData) (string, error) {
+	_, network, err := net.ParseCIDR(d.Get("prefix").(string))
+	if err != nil {
+		return "", err
+	}
+	if (network.IP.To4() != nil) != (d.Get("address_family").(string) == "ipv4") {
+		return "", fmt.Errorf("prefix %s does not match address_family %s", d.Get("prefix").(string), d.Get("address_family").(string))
+	}
+	if d.Get("next_hop_address").(string) == "" && d.Get("next_hop_interface").(string) == "" {
+		return "", errors.New("one of next_hop_address or next_hop_interface is required")
+	}
+	prefixLength, _ := network.Mask.Size()
+
+	return payload.NetconfCiscoStaticRouteURL(
+		d.Get("device").(string),
+		d.Get("vrf").(string),
+		d.Get("address_family").(string),
+		network.IP.String(),
+		prefixLength,
+		d.Get("next_hop_interface").(string),
+		d.Get("next_hop_address").(string),
+	), nil
+}
+
+func resourceCreateCiscoStaticRoute(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	route := payload.CiscoStaticRoute{
+		InterfaceName:  d.Get("next_hop_interface").(string),
+		NextHopAddress: d.Get("next_hop_address").(string),
+		Distance:       d.Get("distance").(int),
+		Tag:            d.Get("tag").(int),
+		Description:    d.Get("description").(string),
+	}
+
+	url, err := staticRouteURL(d)
+	if err != nil {
+		return err
+	}
+
+	payloadBody, err := payload.NetconfCiscoStaticRoutePayload(route)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoStaticRoute(d, m))
+	})
+}
+
+func resourceReadCiscoStaticRoute(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url, err := staticRouteURL(d)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	route, err := payload.ParseNetconfCiscoStaticRoutePayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	nextHop := strings.TrimSpace(route.InterfaceName + " " + route.NextHopAddress)
+	d.SetId(fmt.Sprintf("%s/%s/%s", d.Get("vrf").(string), d.Get("prefix").(string), nextHop))
+	d.Set("distance", route.Distance)
+	d.Set("tag", route.Tag)
+	d.Set("description", route.Description)
+	return nil
+}
+
+func resourceDeleteCiscoStaticRoute(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url, err := staticRouteURL(d)
+	if err != nil {
+		return err
+	}
+
+	err = deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
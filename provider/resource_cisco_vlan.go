@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourceCiscoVlan() *schema.Resource {
@@ -38,76 +39,68 @@ func resourceCiscoVlan() *schema.Resource {
 				Description: "Device for this vlan",
 			},
 			"mtu": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "MTU size",
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "MTU size",
+				ValidateFunc: validateYang(payload.InterfaceConfigurationMtusMtu{}, "Mtu"),
 			},
 			"interface_mode": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "interface-mode-non-physical, ie l2-transport",
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "interface-mode-non-physical, ie l2-transport",
+				ValidateFunc: validation.StringInSlice(payload.InterfaceModeEnumValues, false),
 			},
 			"outer_tag_type": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "outer-tag-type for this vlan ie match-untagged",
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "outer-tag-type for this vlan ie match-untagged",
+				ValidateFunc: validation.StringInSlice(payload.MatchValues, false),
 			},
 			"tag_type": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "inner-tag-type and outer-tag-type ie match-dot1",
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "inner-tag-type and outer-tag-type ie match-dot1",
+				ValidateFunc: validation.StringInSlice(payload.MatchValues, false),
 			},
 			"inner_tag": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "outer-tag-type for this vlan ie match-untagged",
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "outer-tag-type for this vlan ie match-untagged",
+				ValidateFunc: validateYang(payload.InterfaceConfigurationEthernetServiceRewrite{}, "InnerTagValue"),
 			},
 			"outer_tag": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "outer-tag-type for this vlan ie match-untagged",
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "outer-tag-type for this vlan ie match-untagged",
+				ValidateFunc: validateYang(payload.InterfaceConfigurationEthernetServiceRewrite{}, "OuterTagValue"),
+			},
+			"service_policy_input": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Policy-map applied to traffic received on this vlan, ie lsc_cisco_policy_map name",
+				ValidateFunc: validateYang(payload.InterfaceConfigurationQosInputServicePolicy{}, "ServicePolicyName"),
+			},
+			"service_policy_output": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Policy-map applied to traffic sent on this vlan, ie lsc_cisco_policy_map name",
+				ValidateFunc: validateYang(payload.InterfaceConfigurationQosOutputServicePolicy{}, "ServicePolicyName"),
 			},
 		},
 		Create: resourceCreateCiscoVlan,
 		Read:   resourceReadCiscoVlan,
-		Update: resourceCreateCiscoVlan,
+		Update: resourceUpdateCiscoVlan,
 		Delete: resourceDeleteCiscoVlan,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(45 * time.Second),
+			Update: schema.DefaultTimeout(45 * time.Second),
 		}}
 }
 
 func resourceCreateCiscoVlan(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*client.Client)
 
-	var MTUbody = []payload.Mtu{
-		{
-			Owner: "sub_vlan",
-			Mtu:   d.Get("mtu").(int),
-		},
-	}
-
-	device := payload.CiscoVlan{
-		InterfaceName:            d.Get("name").(string),
-		Description:              d.Get("description").(string),
-		Active:                   "pre",
-		InterfaceModeNonPhysical: d.Get("interface_mode").(string),
-		CiscoIOSXRL2EthInfraCfgEthernetService: payload.CiscoIOSXRL2EthInfraCfgEthernetService{
-			Encapsulation: payload.Encapsulation{
-				OuterTagType: d.Get("outer_tag_type").(string),
-			},
-			Rewrite: payload.Rewrite{
-				InnerTagType:  d.Get("tag_type").(string),
-				OuterTagType:  d.Get("tag_type").(string),
-				InnerTagValue: d.Get("inner_tag").(int),
-				OuterTagValue: d.Get("outer_tag").(int),
-				RewriteType:   "push2",
-			},
-		},
-		Mtus: payload.Mtus{
-			Mtu: MTUbody,
-		},
-	}
+	device := expandCiscoVlan(d)
 
 	url := payload.NetconfCiscoVlanURL(d.Get("device").(string), d.Get("name").(string))
 
@@ -118,8 +111,11 @@ func resourceCreateCiscoVlan(d *schema.ResourceData, m interface{}) error {
 	}
 
 	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		err = apiClient.PutNetconf(url, payloadBody)
+		err = writeNetconf(d, apiClient, url, payloadBody)
 
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
 		if err != nil {
 			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
 		}
@@ -128,12 +124,98 @@ func resourceCreateCiscoVlan(d *schema.ResourceData, m interface{}) error {
 	})
 }
 
+// resourceUpdateCiscoVlan merges the changes into the interface-configuration so leaves the
+// provider doesn't manage are kept. Service policies are keyed by name, so a changed policy
+// is removed before the new one is merged in, and a description that is no longer set is
+// removed as empty leaves are left out of the merge. The changes fail when the
+// interface-configuration was changed since it was last read
+func resourceUpdateCiscoVlan(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	device := expandCiscoVlan(d)
+
+	url := payload.NetconfCiscoVlanURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoVlanPayload(device)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	patch := payload.NewYangPatch("lsc_cisco_vlan " + device.InterfaceName)
+	if d.HasChange("service_policy_input") {
+		patch.Remove("/Cisco-IOS-XR-qos-ma-cfg:qos/input")
+	}
+	if d.HasChange("service_policy_output") {
+		patch.Remove("/Cisco-IOS-XR-qos-ma-cfg:qos/output")
+	}
+	if d.HasChange("description") && device.Description == "" {
+		patch.Remove("/description")
+	}
+	patch.Merge("", payloadBody)
+
+	return resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		_, err = apiClient.YangPatchNetconfIfUnchanged(url, patch, stateVersion(d, url))
+
+		if errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(changedOutsideError(url, err))
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadCiscoVlan(d, m))
+	})
+}
+
+// expandCiscoVlan builds the interface-configuration of the vlan from the resource
+func expandCiscoVlan(d *schema.ResourceData) payload.InterfaceConfiguration {
+	return payload.InterfaceConfiguration{
+		InterfaceName:            d.Get("name").(string),
+		Description:              d.Get("description").(string),
+		Active:                   "pre",
+		InterfaceModeNonPhysical: payload.InterfaceModeEnum(d.Get("interface_mode").(string)),
+		EthernetService: &payload.InterfaceConfigurationEthernetService{
+			Encapsulation: &payload.InterfaceConfigurationEthernetServiceEncapsulation{
+				OuterTagType: payload.Match(d.Get("outer_tag_type").(string)),
+			},
+			Rewrite: &payload.InterfaceConfigurationEthernetServiceRewrite{
+				InnerTagType:  payload.Match(d.Get("tag_type").(string)),
+				OuterTagType:  payload.Match(d.Get("tag_type").(string)),
+				InnerTagValue: uint32(d.Get("inner_tag").(int)),
+				OuterTagValue: uint32(d.Get("outer_tag").(int)),
+				RewriteType:   payload.RewritePush2,
+			},
+		},
+		Mtus: &payload.InterfaceConfigurationMtus{
+			Mtu: []payload.InterfaceConfigurationMtusMtu{
+				{
+					Owner: "sub_vlan",
+					Mtu:   uint32(d.Get("mtu").(int)),
+				},
+			},
+		},
+		Qos: payload.NewInterfaceQos(d.Get("service_policy_input").(string), d.Get("service_policy_output").(string)),
+	}
+}
+
+// ciscoVlanFields are the nodes of the interface-configuration the resource reads
+var ciscoVlanFields = []string{
+	"active",
+	"interface-name",
+	"description",
+	"interface-mode-non-physical",
+	"mtus",
+	"Cisco-IOS-XR-l2-eth-infra-cfg:ethernet-service(encapsulation;rewrite)",
+	"Cisco-IOS-XR-qos-ma-cfg:qos",
+}
+
 func resourceReadCiscoVlan(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*client.Client)
 
 	url := payload.NetconfCiscoVlanURL(d.Get("device").(string), d.Get("name").(string))
 
-	bodyBytes, err := apiClient.GetNetconf(url)
+	bodyBytes, err := readNetconf(d, apiClient, url, ciscoVlanFields...)
 	if err != nil {
 		log.Print("[Error] GET: ", err)
 		if errors.Is(err, client.ErrNotFound) {
@@ -146,19 +228,35 @@ func resourceReadCiscoVlan(d *schema.ResourceData, m interface{}) error {
 	device, err := payload.ParseNetconfCiscoVlanPayload(bodyBytes)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil
+		return err
 	}
 
 	d.SetId(device.InterfaceName)
 	d.Set("name", device.InterfaceName)
 	d.Set("description", device.Description)
-	d.Set("mtu", device.Mtus.Mtu[0].Mtu)
-	d.Set("interface_mode", device.InterfaceModeNonPhysical)
+	mtu := 0
+	if device.Mtus != nil && len(device.Mtus.Mtu) > 0 {
+		mtu = int(device.Mtus.Mtu[0].Mtu)
+	}
+	d.Set("mtu", mtu)
+	d.Set("interface_mode", string(device.InterfaceModeNonPhysical))
 	d.Set("description", device.Description)
-	d.Set("outer_tag_type", device.CiscoIOSXRL2EthInfraCfgEthernetService.Encapsulation.OuterTagType)
-	d.Set("tag_type", device.CiscoIOSXRL2EthInfraCfgEthernetService.Rewrite.InnerTagType)
-	d.Set("inner_tag", device.CiscoIOSXRL2EthInfraCfgEthernetService.Rewrite.InnerTagValue)
-	d.Set("outer_tag", device.CiscoIOSXRL2EthInfraCfgEthernetService.Rewrite.OuterTagValue)
+	encapsulation := &payload.InterfaceConfigurationEthernetServiceEncapsulation{}
+	rewrite := &payload.InterfaceConfigurationEthernetServiceRewrite{}
+	if device.EthernetService != nil {
+		if device.EthernetService.Encapsulation != nil {
+			encapsulation = device.EthernetService.Encapsulation
+		}
+		if device.EthernetService.Rewrite != nil {
+			rewrite = device.EthernetService.Rewrite
+		}
+	}
+	d.Set("outer_tag_type", string(encapsulation.OuterTagType))
+	d.Set("tag_type", string(rewrite.InnerTagType))
+	d.Set("inner_tag", int(rewrite.InnerTagValue))
+	d.Set("outer_tag", int(rewrite.OuterTagValue))
+	d.Set("service_policy_input", device.Qos.InputPolicy())
+	d.Set("service_policy_output", device.Qos.OutputPolicy())
 	return nil
 }
 
@@ -167,7 +265,10 @@ func resourceDeleteCiscoVlan(d *schema.ResourceData, m interface{}) error {
 
 	url := payload.NetconfCiscoVlanURL(d.Get("device").(string), d.Get("name").(string))
 
-	err := apiClient.DeleteNetconf(url)
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
 	if err != nil {
 		log.Print("[Error]: ", err)
 		return nil
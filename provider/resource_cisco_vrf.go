@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCiscoVrf() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the vrf resource",
+				ForceNew:    true,
+			},
+			"device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Device for this vrf, the vrf and its bgp vrf are recreated on another device",
+				ForceNew:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of vrf",
+			},
+			"address_family": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ipv4",
+				Description:  "Unicast address family of the vrf, ipv4 or ipv6",
+				ValidateFunc: validation.StringInSlice([]string{"ipv4", "ipv6"}, false),
+			},
+			"bgp_as": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "AS of the BGP instance holding the route distinguisher",
+			},
+			"route_distinguisher": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Route distinguisher in ASN:nn or IPv4:nn format, requires bgp_as",
+			},
+			"import_route_targets": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Route targets to import in ASN:nn or IPv4:nn format",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"export_route_targets": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Route targets to export in ASN:nn or IPv4:nn format",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Create: resourceCreateCiscoVrf,
+		Read:   resourceReadCiscoVrf,
+		Update: resourceCreateCiscoVrf,
+		Delete: resourceDeleteCiscoVrf,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateCiscoVrf(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	if d.Get("route_distinguisher").(string) != "" && d.Get("bgp_as").(int) == 0 {
+		return fmt.Errorf("bgp_as is required to set route_distinguisher on vrf %s", d.Get("name").(string))
+	}
+
+	importRouteTargets, err := payload.NewRouteTargets(expandStringSet(d.Get("import_route_targets").(*schema.Set)))
+	if err != nil {
+		return err
+	}
+	exportRouteTargets, err := payload.NewRouteTargets(expandStringSet(d.Get("export_route_targets").(*schema.Set)))
+	if err != nil {
+		return err
+	}
+
+	af := payload.VrfAf{
+		AfName:       d.Get("address_family").(string),
+		SafName:      "unicast",
+		TopologyName: "default",
+		Create:       &payload.Empty{},
+	}
+	if len(importRouteTargets.RouteTargets.RouteTarget) > 0 || len(exportRouteTargets.RouteTargets.RouteTarget) > 0 {
+		af.Bgp = &payload.VrfAfBgp{}
+		if len(importRouteTargets.RouteTargets.RouteTarget) > 0 {
+			af.Bgp.ImportRouteTargets = importRouteTargets
+		}
+		if len(exportRouteTargets.RouteTargets.RouteTarget) > 0 {
+			af.Bgp.ExportRouteTargets = exportRouteTargets
+		}
+	}
+
+	vrf := payload.CiscoVrf{
+		VrfName:     d.Get("name").(string),
+		Create:      &payload.Empty{},
+		Description: d.Get("description").(string),
+		Afs: &payload.VrfAfs{
+			Af: []payload.VrfAf{af},
+		},
+	}
+
+	url := payload.NetconfCiscoVrfURL(d.Get("device").(string), d.Get("name").(string))
+
+	payloadBody, err := payload.NetconfCiscoVrfPayload(vrf)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err = writeNetconf(d, apiClient, url, payloadBody)
+
+		if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !d.IsNewResource() {
+		if err := removeOldCiscoBgpVrf(d, apiClient); err != nil {
+			return err
+		}
+	}
+
+	if rd := d.Get("route_distinguisher").(string); rd != "" {
+		routeDistinguisher, err := payload.ParseRouteDistinguisher(rd)
+		if err != nil {
+			return err
+		}
+
+		bgpVrf := payload.CiscoBgpVrf{
+			VrfName: d.Get("name").(string),
+			VrfGlobal: payload.BgpVrfGlobal{
+				Exists:             &payload.Empty{},
+				RouteDistinguisher: &routeDistinguisher,
+				VrfGlobalAfs: &payload.BgpVrfGlobalAfs{
+					VrfGlobalAf: []payload.BgpVrfGlobalAf{
+						{
+							AfName: d.Get("address_family").(string) + "-unicast",
+							Enable: &payload.Empty{},
+						},
+					},
+				},
+			},
+		}
+
+		bgpURL := payload.NetconfCiscoBgpVrfURL(d.Get("device").(string), d.Get("bgp_as").(int), d.Get("name").(string))
+
+		bgpPayloadBody, err := payload.NetconfCiscoBgpVrfPayload(bgpVrf)
+		if err != nil {
+			log.Print("[Error]: ", err)
+			return err
+		}
+
+		err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+			err = writeNetconf(d, apiClient, bgpURL, bgpPayloadBody)
+
+			if errors.Is(err, client.ErrAlreadyExists) || errors.Is(err, client.ErrPreconditionFailed) {
+				return resource.NonRetryableError(err)
+			}
+			if err != nil {
+				return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceReadCiscoVrf(d, m)
+}
+
+// removeOldCiscoBgpVrf deletes the BGP vrf of the AS the vrf had before an update when it moves
+// to another AS or its route distinguisher is cleared, it would be left on the device otherwise.
+// A vrf moving to another device is recreated, so the device is the same
+func removeOldCiscoBgpVrf(d *schema.ResourceData, apiClient *client.Client) error {
+	oldAS, newAS := d.GetChange("bgp_as")
+	if oldAS.(int) == 0 || (oldAS.(int) == newAS.(int) && d.Get("route_distinguisher").(string) != "") {
+		return nil
+	}
+
+	bgpURL := payload.NetconfCiscoBgpVrfURL(d.Get("device").(string), oldAS.(int), d.Get("name").(string))
+
+	err := apiClient.DeleteNetconf(bgpURL)
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		log.Print("[Error]: ", err)
+		return err
+	}
+	return nil
+}
+
+func resourceReadCiscoVrf(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCiscoVrfURL(d.Get("device").(string), d.Get("name").(string))
+
+	bodyBytes, err := readNetconf(d, apiClient, url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	vrf, err := payload.ParseNetconfCiscoVrfPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(vrf.VrfName)
+	d.Set("name", vrf.VrfName)
+	d.Set("description", vrf.Description)
+
+	importRouteTargets := []string{}
+	exportRouteTargets := []string{}
+	if vrf.Afs != nil && len(vrf.Afs.Af) > 0 {
+		af := vrf.Afs.Af[0]
+		d.Set("address_family", af.AfName)
+		if af.Bgp != nil {
+			importRouteTargets = af.Bgp.ImportRouteTargets.Strings()
+			exportRouteTargets = af.Bgp.ExportRouteTargets.Strings()
+		}
+	}
+	d.Set("import_route_targets", importRouteTargets)
+	d.Set("export_route_targets", exportRouteTargets)
+
+	if d.Get("bgp_as").(int) == 0 {
+		return nil
+	}
+
+	bgpURL := payload.NetconfCiscoBgpVrfURL(d.Get("device").(string), d.Get("bgp_as").(int), d.Get("name").(string))
+
+	bodyBytes, err = readNetconf(d, apiClient, bgpURL)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.Set("route_distinguisher", "")
+			return nil
+		}
+		return err
+	}
+
+	bgpVrf, err := payload.ParseNetconfCiscoBgpVrfPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	if bgpVrf.VrfGlobal.RouteDistinguisher != nil {
+		d.Set("route_distinguisher", bgpVrf.VrfGlobal.RouteDistinguisher.String())
+	} else {
+		d.Set("route_distinguisher", "")
+	}
+	return nil
+}
+
+func resourceDeleteCiscoVrf(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	if d.Get("bgp_as").(int) != 0 {
+		bgpURL := payload.NetconfCiscoBgpVrfURL(d.Get("device").(string), d.Get("bgp_as").(int), d.Get("name").(string))
+
+		err := apiClient.DeleteNetconf(bgpURL)
+		if err != nil && !errors.Is(err, client.ErrNotFound) {
+			log.Print("[Error]: ", err)
+			return err
+		}
+	}
+
+	url := payload.NetconfCiscoVrfURL(d.Get("device").(string), d.Get("name").(string))
+
+	err := deleteNetconf(d, apiClient, url)
+	if errors.Is(err, client.ErrPreconditionFailed) {
+		return err
+	}
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
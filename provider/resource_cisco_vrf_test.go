@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"net/http"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testUpdateCiscoVrf applies an update of the vrf blue with a bgp vrf on AS 65000 to a configuration
+func testUpdateCiscoVrf(t *testing.T, controller *testController, config map[string]interface{}) {
+	r := resourceCiscoVrf()
+	state := &terraform.InstanceState{ID: "blue", Attributes: map[string]string{
+		"id":                     "blue",
+		"name":                   "blue",
+		"device":                 "r1",
+		"address_family":         "ipv4",
+		"bgp_as":                 "65000",
+		"route_distinguisher":    "65000:1",
+		"import_route_targets.#": "0",
+		"export_route_targets.#": "0",
+	}}
+	apiClient := controller.client()
+
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(config), apiClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, apiClient); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateCiscoVrf(t *testing.T) {
+	oldBgpVrf := payload.NetconfCiscoBgpVrfURL("r1", 65000, "blue")
+	newBgpVrf := payload.NetconfCiscoBgpVrfURL("r1", 65001, "blue")
+
+	cases := []struct {
+		name    string
+		config  map[string]interface{}
+		deleted bool
+		put     string
+	}{
+		{
+			name:    "bgp_as changed",
+			config:  map[string]interface{}{"name": "blue", "device": "r1", "bgp_as": 65001, "route_distinguisher": "65000:1"},
+			deleted: true,
+			put:     newBgpVrf,
+		},
+		{
+			name:    "route_distinguisher cleared",
+			config:  map[string]interface{}{"name": "blue", "device": "r1", "bgp_as": 65000},
+			deleted: true,
+		},
+		{
+			name:    "bgp_as removed",
+			config:  map[string]interface{}{"name": "blue", "device": "r1"},
+			deleted: true,
+		},
+		{
+			name:   "route_distinguisher changed",
+			config: map[string]interface{}{"name": "blue", "device": "r1", "bgp_as": 65000, "route_distinguisher": "65000:2"},
+			put:    oldBgpVrf,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := newTestController()
+			defer controller.close()
+			controller.reply(payload.NetconfCiscoVrfURL("r1", "blue"), `{"vrf":[{"vrf-name":"blue"}]}`)
+
+			testUpdateCiscoVrf(t, controller, c.config)
+
+			if deleted := controller.request("DELETE", oldBgpVrf) != nil; deleted != c.deleted {
+				t.Errorf("expected the bgp vrf of AS 65000 deleted %t, got writes %v", c.deleted, controller.writes())
+			}
+			if c.put != "" && controller.request("PUT", c.put) == nil {
+				t.Errorf("expected the bgp vrf to be put to %s, got writes %v", c.put, controller.writes())
+			}
+		})
+	}
+}
+
+// The vrf and its bgp vrf would be left on the old device, so a vrf is recreated on another one
+func TestCiscoVrfDeviceForcesNew(t *testing.T) {
+	r := resourceCiscoVrf()
+	state := &terraform.InstanceState{ID: "blue", Attributes: map[string]string{
+		"id":             "blue",
+		"name":           "blue",
+		"device":         "r1",
+		"address_family": "ipv4",
+	}}
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "blue", "device": "r2"}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.RequiresNew() {
+		t.Errorf("expected a device change to recreate the vrf, got %v", diff.Attributes)
+	}
+}
+
+// The bgp vrf is put with the version it was read with, like the vrf
+func TestUpdateCiscoBgpVrfIfUnchanged(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	bgpVrf := payload.NetconfCiscoBgpVrfURL("r1", 65000, "blue")
+	controller.reply(payload.NetconfCiscoVrfURL("r1", "blue"), `{"vrf":[{"vrf-name":"blue"}]}`)
+	controller.reply(bgpVrf, `{"vrf":[{"vrf-name":"blue","vrf-global":{"exists":[null],"route-distinguisher":{"type":"as","as-xx":0,"as":65000,"as-index":1}}}]}`)
+	controller.headers[bgpVrf] = http.Header{"Etag": []string{`"7"`}}
+
+	r := Provider().(*schema.Provider).ResourcesMap["lsc_cisco_vrf"]
+	apiClient := controller.client()
+	state, err := r.Refresh(&terraform.InstanceState{ID: "blue", Attributes: map[string]string{
+		"id":     "blue",
+		"name":   "blue",
+		"device": "r1",
+		"bgp_as": "65000",
+	}}, apiClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "blue", "device": "r1", "bgp_as": 65000, "route_distinguisher": "65000:2",
+	}), apiClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, apiClient); err != nil {
+		t.Fatal(err)
+	}
+
+	if put := controller.request("PUT", bgpVrf); put == nil || put.header.Get("If-Match") != `"7"` {
+		t.Errorf("expected the bgp vrf to be put with If-Match \"7\", got writes %v", controller.writes())
+	}
+}
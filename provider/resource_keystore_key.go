@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceKeystoreKey() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"key_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the key in the netconf-keystore, referenced by key_id of lsc_netconf_device credentials",
+				ForceNew:    true,
+			},
+			"private_key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key",
+				ForceNew:    true,
+			},
+			"passphrase": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Passphrase of the private key",
+				ForceNew:    true,
+			},
+		},
+		Create: resourceCreateKeystoreKey,
+		Read:   resourceReadKeystoreKey,
+		Delete: resourceDeleteKeystoreKey,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Second),
+		}}
+}
+
+func resourceCreateKeystoreKey(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	entry := payload.KeystoreEntry{
+		KeyID:      d.Get("key_id").(string),
+		PrivateKey: d.Get("private_key").(string),
+		Passphrase: d.Get("passphrase").(string),
+	}
+
+	payloadBody, err := payload.NetconfKeystoreAddPayload(entry)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, err = apiClient.InvokeRPC(payload.NetconfKeystoreAddURL(), payloadBody)
+
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Error from controller: %s", err))
+		}
+
+		return resource.NonRetryableError(resourceReadKeystoreKey(d, m))
+	})
+}
+
+func resourceReadKeystoreKey(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfKeystoreEntryURL(d.Get("key_id").(string))
+
+	bodyBytes, err := apiClient.GetNetconf(url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	entry, err := payload.ParseNetconfKeystoreEntryPayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	// The controller stores the private key and passphrase encrypted, so they
+	// can't be compared with the configuration and are left as they are in state
+	d.SetId(entry.KeyID)
+	return nil
+}
+
+func resourceDeleteKeystoreKey(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	payloadBody, err := payload.NetconfKeystoreRemovePayload(d.Get("key_id").(string))
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	_, err = apiClient.InvokeRPC(payload.NetconfKeystoreRemoveURL(), payloadBody)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
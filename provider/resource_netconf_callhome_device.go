@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceNetconfCallhomeDevice() *schema.Resource {
+	fmt.Print()
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"unique_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Unique ID of the device, also the name of its node in the netconf topology",
+				ForceNew:    true,
+			},
+			"ssh_host_key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Base64 encoded SSH host key the device presents when it calls home",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Username to authenticate to the device",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password to authenticate to the device, only its hash is kept in state",
+				StateFunc:   hashPasswordState,
+			},
+			"connection_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Connection status of the device in the netconf topology",
+			},
+		},
+		Create: resourceCreateNetconfCallhomeDevice,
+		Read:   resourceReadNetconfCallhomeDevice,
+		Update: resourceCreateNetconfCallhomeDevice,
+		Delete: resourceDeleteNetconfCallhomeDevice,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		}}
+}
+
+func resourceCreateNetconfCallhomeDevice(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	device := payload.NetconfCallhomeDevice{
+		UniqueID:   d.Get("unique_id").(string),
+		SSHHostKey: d.Get("ssh_host_key").(string),
+		Credentials: &payload.NetconfCallhomeCredentials{
+			Username:  d.Get("username").(string),
+			Passwords: []string{d.Get("password").(string)},
+		},
+	}
+
+	url := payload.NetconfCallhomeDeviceURL(d.Get("unique_id").(string))
+
+	// State only has the hash of an unchanged password, the passwords allowed on the controller are kept
+	if !d.IsNewResource() && !d.HasChange("password") {
+		passwords, err := configuredCallhomePasswords(apiClient, url, d.Get("password").(string))
+		if err != nil {
+			return err
+		}
+		device.Credentials.Passwords = passwords
+	}
+
+	payloadBody, err := payload.NetconfCallhomeDevicePayload(device)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	err = writeNetconf(d, apiClient, url, payloadBody)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+	apiClient.ForgetYangModules(device.UniqueID)
+
+	// The device is allowed from here on, so it is tainted rather than lost if it never calls home
+	d.SetId(device.UniqueID)
+
+	// An update applies the next time the device calls home, which may be long after it
+	if !d.IsNewResource() {
+		return resourceReadNetconfCallhomeDevice(d, m)
+	}
+
+	// Call-home devices connect to the controller, wait for the device to call home
+	// and appear connected in the netconf topology
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		bodyBytes, err := apiClient.GetNetconf(payload.NetconfMountURLOperational(d.Get("unique_id").(string)))
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("Waiting for device %s to call home: %s", d.Get("unique_id").(string), err))
+		}
+
+		node, err := payload.ParseNetconfOperationalMountPayload(bodyBytes)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		log.Print("[Status]: ", node.Status)
+		if node.Status != "connected" {
+			return resource.RetryableError(fmt.Errorf("Device %s is %s", d.Get("unique_id").(string), node.Status))
+		}
+
+		return resource.NonRetryableError(resourceReadNetconfCallhomeDevice(d, m))
+	})
+}
+
+// configuredCallhomePasswords returns the passwords allowed for a call-home device on the
+// controller, the first has to match the hash of the password in state
+func configuredCallhomePasswords(apiClient *client.Client, url string, passwordHash string) ([]string, error) {
+	bodyBytes, err := apiClient.GetNetconf(url)
+	if err != nil {
+		return nil, fmt.Errorf("reading the passwords of %s to keep them: %w", url, err)
+	}
+	configured, err := payload.ParseNetconfCallhomeDevicePayload(bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if configured.Credentials == nil || len(configured.Credentials.Passwords) == 0 ||
+		hashPassword(configured.Credentials.Passwords[0]) != hashPasswordState(passwordHash) {
+		return nil, fmt.Errorf("the password of call-home device %s was changed outside of terraform, set it again", configured.UniqueID)
+	}
+	return configured.Credentials.Passwords, nil
+}
+
+func resourceReadNetconfCallhomeDevice(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCallhomeDeviceURL(d.Get("unique_id").(string))
+
+	bodyBytes, err := apiClient.GetNetconf(url)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	device, err := payload.ParseNetconfCallhomeDevicePayload(bodyBytes)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return err
+	}
+
+	d.SetId(device.UniqueID)
+	d.Set("ssh_host_key", device.SSHHostKey)
+	if device.Credentials != nil {
+		d.Set("username", device.Credentials.Username)
+		if len(device.Credentials.Passwords) > 0 {
+			d.Set("password", hashPassword(device.Credentials.Passwords[0]))
+		}
+	}
+
+	// The device may not have called home yet, which is not drift of the allowed device
+	status := "disconnected"
+	bodyBytes, err = apiClient.GetNetconf(payload.NetconfMountURLOperational(device.UniqueID))
+	if err == nil {
+		if node, err := payload.ParseNetconfOperationalMountPayload(bodyBytes); err == nil {
+			status = node.Status
+		}
+	}
+	d.Set("connection_status", status)
+	return nil
+}
+
+func resourceDeleteNetconfCallhomeDevice(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*client.Client)
+
+	url := payload.NetconfCallhomeDeviceURL(d.Get("unique_id").(string))
+
+	err := apiClient.DeleteNetconf(url)
+	if err != nil {
+		log.Print("[Error]: ", err)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testUpdateCallhomeDevice applies an update of the call-home device r2 allowed with the password secret
+func testUpdateCallhomeDevice(t *testing.T, controller *testController, config map[string]interface{}) error {
+	r := resourceNetconfCallhomeDevice()
+	state := &terraform.InstanceState{ID: "r2", Attributes: map[string]string{
+		"id":           "r2",
+		"unique_id":    "r2",
+		"ssh_host_key": "AAAA",
+		"username":     "admin",
+		"password":     hashPassword("secret"),
+	}}
+	apiClient := controller.client()
+
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(config), apiClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Apply(state, diff, apiClient)
+	return err
+}
+
+func TestUpdateCallhomeDevice(t *testing.T) {
+	cases := []struct {
+		name      string
+		password  string
+		passwords string
+	}{
+		{name: "host key rotated", password: "secret", passwords: `"passwords":["secret","fallback"]`},
+		{name: "password changed", password: "changed", passwords: `"passwords":["changed"]`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := newTestController()
+			defer controller.close()
+			url := payload.NetconfCallhomeDeviceURL("r2")
+			controller.reply(url, `{"device":[{"unique-id":"r2","ssh-host-key":"AAAA","credentials":{"username":"admin","passwords":["secret","fallback"]}}]}`)
+
+			err := testUpdateCallhomeDevice(t, controller, map[string]interface{}{
+				"unique_id":    "r2",
+				"ssh_host_key": "BBBB",
+				"username":     "admin",
+				"password":     c.password,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			put := controller.request("PUT", url)
+			if put == nil {
+				t.Fatalf("expected the allowed device to be updated, got writes %v", controller.writes())
+			}
+			if strings.Contains(put.body, passwordHashPrefix) || !strings.Contains(put.body, c.passwords) {
+				t.Errorf("expected the update to send %s, got %s", c.passwords, put.body)
+			}
+			// The device is not connected, updates don't wait for it to call home again
+			if controller.request("GET", payload.NetconfMountURLOperational("r2")) == nil {
+				t.Error("expected the connection status to be read")
+			}
+		})
+	}
+}
+
+func TestUpdateCallhomeDevicePasswordChangedOutside(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	url := payload.NetconfCallhomeDeviceURL("r2")
+	controller.reply(url, `{"device":[{"unique-id":"r2","ssh-host-key":"AAAA","credentials":{"username":"admin","passwords":["other"]}}]}`)
+
+	err := testUpdateCallhomeDevice(t, controller, map[string]interface{}{
+		"unique_id":    "r2",
+		"ssh_host_key": "BBBB",
+		"username":     "admin",
+		"password":     "secret",
+	})
+	if err == nil || !strings.Contains(err.Error(), "changed outside of terraform") {
+		t.Fatalf("expected the password changed outside of terraform to fail the update, got %v", err)
+	}
+	if writes := controller.writes(); len(writes) != 0 {
+		t.Errorf("expected no write, got %v", writes)
+	}
+}
@@ -3,49 +3,224 @@ package provider
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
 	"qasimraz/terraform-provider-lsc-demo/api/client"
 	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourceNetconfDevice() *schema.Resource {
 	fmt.Print()
 	return &schema.Resource{
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the resource, also acts as it's unique ID",
-				ForceNew:    true,
+		Schema:        netconfDeviceSchema(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceNetconfDeviceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceNetconfDeviceUpgradeV0,
 			},
-			"ip_address": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "IP Address of Netconf Device",
-			},
-			"port": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "Port of the Netconf Device, Default is 830",
-			},
-			"username": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Username to authenticate to the device",
+		},
+		CustomizeDiff: resourceNetconfDevicePasswordDiff,
+		Create:        resourceCreateNetconfDevice,
+		Read:          resourceReadNetconfDevice,
+		Update:        resourceCreateNetconfDevice,
+		Delete:        resourceDeleteNetconfDevice,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// resourceNetconfDeviceV0 is lsc_netconf_device before passwords were hashed in state
+func resourceNetconfDeviceV0() *schema.Resource {
+	deviceSchema := netconfDeviceSchema()
+	delete(deviceSchema, "password_env")
+	delete(deviceSchema, "password_file")
+	delete(deviceSchema, "password_hash")
+	return &schema.Resource{Schema: deviceSchema}
+}
+
+func netconfDeviceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The name of the resource, also acts as it's unique ID",
+			ForceNew:    true,
+		},
+		"ip_address": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "IP Address of Netconf Device",
+		},
+		"port": {
+			Type:         schema.TypeInt,
+			Required:     true,
+			Description:  "Port of the Netconf Device, Default is 830",
+			ValidateFunc: validateYang(payload.Netconf{}, "Port"),
+		},
+		"username": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Description:   "Username to authenticate to the device",
+			ConflictsWith: []string{"credentials"},
+		},
+		"password": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			Description:   "Password to authenticate to the device, only its hash is kept in state",
+			StateFunc:     hashPasswordState,
+			ConflictsWith: []string{"credentials", "password_env", "password_file"},
+		},
+		"password_env": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Description:   "Environment variable to read the password from",
+			ConflictsWith: []string{"credentials", "password", "password_file"},
+		},
+		"password_file": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Description:   "File to read the password from",
+			ConflictsWith: []string{"credentials", "password", "password_env"},
+		},
+		"password_hash": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Hash of the password configured on the controller, used to detect drift",
+		},
+		"credentials": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			Description:   "Credentials to authenticate to the device, instead of username and password",
+			ConflictsWith: []string{"username", "password", "password_env", "password_file"},
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						Description:  "login-password, login-password-unencrypted or key-based",
+						ValidateFunc: validation.StringInSlice([]string{"login-password", "login-password-unencrypted", "key-based"}, false),
+					},
+					"username": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Username to authenticate to the device",
+					},
+					"password": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Password for login-password and login-password-unencrypted credentials, only its hash is kept in state",
+						StateFunc:   hashPasswordState,
+					},
+					"key_id": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "ID of the lsc_keystore_key for key-based credentials",
+					},
+				},
 			},
-			"password": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Password to authenticate to the device",
+		},
+		"tcp_only": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			Description: "Use plain TCP instead of SSH to connect to the device",
+		},
+		"schemaless": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			Description: "Mount the device without downloading its YANG schemas",
+		},
+		"keepalive_delay": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Seconds between keepalive RPCs, 0 disables keepalives",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"connection_timeout_millis": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Milliseconds to wait for the connection to be established",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"default_request_timeout_millis": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Milliseconds to wait for a reply to an RPC",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"max_connection_attempts": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Connection attempts before giving up, 0 retries forever",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"between_attempts_timeout_millis": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Initial milliseconds to wait between connection attempts",
+			ValidateFunc: validation.IntBetween(0, 65535),
+		},
+		"sleep_factor": {
+			Type:        schema.TypeFloat,
+			Optional:    true,
+			Computed:    true,
+			Description: "Multiplier applied to the wait between connection attempts",
+		},
+		"reconnect_on_changed_schema": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			Description: "Reconnect when the device reports a schema change",
+		},
+		"concurrent_rpc_limit": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Maximum RPCs in flight to the device, 0 is unlimited",
+			ValidateFunc: validation.IntBetween(0, 65535),
+		},
+		"yang_module_capabilities": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "YANG module capabilities to use instead of, or along with, the ones the device advertises",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"override": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Replace the advertised capabilities instead of adding to them",
+					},
+					"capabilities": {
+						Type:        schema.TypeList,
+						Required:    true,
+						Description: "Capabilities in (namespace?revision=date)module format",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+				},
 			},
 		},
-		Create: resourceCreateNetconfDevice,
-		Read:   resourceReadNetconfDevice,
-		Update: resourceCreateNetconfDevice,
-		Delete: resourceDeleteNetconfDevice,
 	}
 }
 
@@ -53,50 +228,92 @@ func resourceCreateNetconfDevice(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*client.Client)
 
 	device := payload.Netconf{
-		Name:      d.Get("name").(string),
-		Port:      d.Get("port").(int),
-		IPAddress: d.Get("ip_address").(string),
-		Username:  d.Get("username").(string),
-		Password:  d.Get("password").(string),
+		NodeId:   d.Get("name").(string),
+		Port:     uint16(d.Get("port").(int)),
+		Host:     d.Get("ip_address").(string),
+		Username: d.Get("username").(string),
+
+		TcpOnly:                      optionalBool(d, "tcp_only"),
+		Schemaless:                   optionalBool(d, "schemaless"),
+		KeepaliveDelay:               optionalUint32(d, "keepalive_delay"),
+		ConnectionTimeoutMillis:      optionalUint32(d, "connection_timeout_millis"),
+		DefaultRequestTimeoutMillis:  optionalUint32(d, "default_request_timeout_millis"),
+		MaxConnectionAttempts:        optionalUint32(d, "max_connection_attempts"),
+		BetweenAttemptsTimeoutMillis: optionalUint16(d, "between_attempts_timeout_millis"),
+		SleepFactor:                  optionalFloat(d, "sleep_factor"),
+		ReconnectOnChangedSchema:     optionalBool(d, "reconnect_on_changed_schema"),
+		ConcurrentRpcLimit:           optionalUint16(d, "concurrent_rpc_limit"),
+	}
+
+	password, err := resolveNetconfPassword(d)
+	if err != nil {
+		return err
+	}
+	device.Password = password
+
+	if err := expandNetconfCredentials(d, &device); err != nil {
+		return err
+	}
+	if !d.IsNewResource() {
+		if err := keepConfiguredNetconfPasswords(apiClient, &device); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range d.Get("yang_module_capabilities").([]interface{}) {
+		capabilities := v.(map[string]interface{})
+		override := capabilities["override"].(bool)
+		device.YangModuleCapabilities = &payload.NetconfYangModuleCapabilities{
+			Override: &override,
+		}
+		for _, capability := range capabilities["capabilities"].([]interface{}) {
+			device.YangModuleCapabilities.Capability = append(device.YangModuleCapabilities.Capability, capability.(string))
+		}
 	}
 
 	url := payload.NetconfMountURL(d.Get("name").(string))
 
+	// The device may advertise other modules once it is remounted
+	apiClient.ForgetYangModules(d.Get("name").(string))
+
 	payloadBody, err := payload.NetconfMountPayload(device)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil
+		return err
 	}
 
-	err = apiClient.PutNetconf(url, payloadBody)
+	err = writeNetconf(d, apiClient, url, payloadBody)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil
+		return err
+	}
+
+	// The device is mounted from here on, so it is tainted rather than lost if it never connects
+	d.SetId(device.NodeId)
+
+	timeout := d.Timeout(schema.TimeoutCreate)
+	if !d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutUpdate)
 	}
 
 	// Verify netconf mount connects succesfully
-	for {
-		time.Sleep(5 * time.Second)
-		url := payload.NetconfMountURLOperational(d.Get("name").(string))
-		bodyBytes, err := apiClient.GetNetconf(url)
+	return resource.Retry(timeout, func() *resource.RetryError {
+		bodyBytes, err := apiClient.GetNetconf(payload.NetconfMountURLOperational(device.NodeId))
 		if err != nil {
-			log.Print("[Error]: ", err)
-			return nil
+			return resource.RetryableError(fmt.Errorf("Waiting for device %s to be mounted: %s", device.NodeId, err))
 		}
 
-		device, err := payload.ParseNetconfOperationalMountPayload(bodyBytes)
+		node, err := payload.ParseNetconfOperationalMountPayload(bodyBytes)
 		if err != nil {
-			log.Print("[Error]: ", err)
-			return nil
+			return resource.NonRetryableError(err)
 		}
-		log.Print("[Status]: ", device.Status)
-		if device.Status == "connected" {
-			break
-		}
-	}
 
-	d.SetId(device.Name)
-	return nil
+		log.Print("[Status]: ", node.Status)
+		if node.Status != "connected" {
+			return resource.RetryableError(fmt.Errorf("Device %s is %s", device.NodeId, node.Status))
+		}
+		return nil
+	})
 }
 
 func resourceReadNetconfDevice(d *schema.ResourceData, m interface{}) error {
@@ -116,15 +333,58 @@ func resourceReadNetconfDevice(d *schema.ResourceData, m interface{}) error {
 	device, err := payload.ParseNetconfMountPayload(bodyBytes)
 	if err != nil {
 		log.Print("[Error]: ", err)
-		return nil
+		return err
 	}
 
-	d.SetId(device.Name)
-	d.Set("name", device.Name)
-	d.Set("port", device.Port)
-	d.Set("ip_address", device.IPAddress)
+	d.SetId(device.NodeId)
+	d.Set("name", device.NodeId)
+	d.Set("port", int(device.Port))
+	d.Set("ip_address", device.Host)
 	d.Set("username", device.Username)
-	d.Set("password", device.Password)
+	d.Set("password_hash", hashPassword(device.Password))
+	d.Set("credentials", flattenNetconfCredentials(d, device))
+
+	// Unset optional parameters fall back to the controller defaults, which aren't
+	// returned in the config datastore, so only the ones present are read back
+	if device.TcpOnly != nil {
+		d.Set("tcp_only", *device.TcpOnly)
+	}
+	if device.Schemaless != nil {
+		d.Set("schemaless", *device.Schemaless)
+	}
+	if device.KeepaliveDelay != nil {
+		d.Set("keepalive_delay", int(*device.KeepaliveDelay))
+	}
+	if device.ConnectionTimeoutMillis != nil {
+		d.Set("connection_timeout_millis", int(*device.ConnectionTimeoutMillis))
+	}
+	if device.DefaultRequestTimeoutMillis != nil {
+		d.Set("default_request_timeout_millis", int(*device.DefaultRequestTimeoutMillis))
+	}
+	if device.MaxConnectionAttempts != nil {
+		d.Set("max_connection_attempts", int(*device.MaxConnectionAttempts))
+	}
+	if device.BetweenAttemptsTimeoutMillis != nil {
+		d.Set("between_attempts_timeout_millis", int(*device.BetweenAttemptsTimeoutMillis))
+	}
+	if device.SleepFactor != nil {
+		d.Set("sleep_factor", *device.SleepFactor)
+	}
+	if device.ReconnectOnChangedSchema != nil {
+		d.Set("reconnect_on_changed_schema", *device.ReconnectOnChangedSchema)
+	}
+	if device.ConcurrentRpcLimit != nil {
+		d.Set("concurrent_rpc_limit", int(*device.ConcurrentRpcLimit))
+	}
+
+	yangModuleCapabilities := []interface{}{}
+	if device.YangModuleCapabilities != nil {
+		yangModuleCapabilities = append(yangModuleCapabilities, map[string]interface{}{
+			"override":     device.YangModuleCapabilities.Override != nil && *device.YangModuleCapabilities.Override,
+			"capabilities": device.YangModuleCapabilities.Capability,
+		})
+	}
+	d.Set("yang_module_capabilities", yangModuleCapabilities)
 	return nil
 }
 
@@ -138,7 +398,172 @@ func resourceDeleteNetconfDevice(d *schema.ResourceData, m interface{}) error {
 		log.Print("[Error]: ", err)
 		return nil
 	}
+	apiClient.ForgetYangModules(d.Get("name").(string))
 
 	d.SetId("")
 	return nil
 }
+
+// expandNetconfCredentials sets the credentials of the mount from the username and
+// password attributes or the credentials block
+func expandNetconfCredentials(d *schema.ResourceData, device *payload.Netconf) error {
+	credentials := d.Get("credentials").([]interface{})
+	if len(credentials) == 0 {
+		if device.Username == "" || device.Password == "" {
+			return fmt.Errorf("username and password or credentials are required for device %s", device.NodeId)
+		}
+		return nil
+	}
+
+	credential := credentials[0].(map[string]interface{})
+	username := credential["username"].(string)
+	password := credential["password"].(string)
+	keyID := credential["key_id"].(string)
+
+	switch credential["type"].(string) {
+	case "login-password", "login-password-unencrypted":
+		if password == "" || keyID != "" {
+			return fmt.Errorf("%s credentials of device %s need a password and no key_id", credential["type"].(string), device.NodeId)
+		}
+		if credential["type"].(string) == "login-password" {
+			device.LoginPassword = &payload.NetconfLoginPassword{Username: username, Password: password}
+		} else {
+			device.LoginPasswordUnencrypted = &payload.NetconfLoginPasswordUnencrypted{Username: username, Password: password}
+		}
+	case "key-based":
+		if keyID == "" || password != "" {
+			return fmt.Errorf("key-based credentials of device %s need a key_id and no password", device.NodeId)
+		}
+		device.KeyBased = &payload.NetconfKeyBased{Username: username, KeyId: keyID}
+	}
+	return nil
+}
+
+// flattenNetconfCredentials returns the credentials block of a mount with the password hashed,
+// the controller encrypts login-password credentials so their hash is kept from state
+func flattenNetconfCredentials(d *schema.ResourceData, device payload.Netconf) []interface{} {
+	credential := map[string]interface{}{}
+	switch {
+	case device.LoginPassword != nil:
+		credential["type"] = "login-password"
+		credential["username"] = device.LoginPassword.Username
+		credential["password"] = hashPasswordState(d.Get("credentials.0.password"))
+	case device.LoginPasswordUnencrypted != nil:
+		credential["type"] = "login-password-unencrypted"
+		credential["username"] = device.LoginPasswordUnencrypted.Username
+		credential["password"] = hashPassword(device.LoginPasswordUnencrypted.Password)
+	case device.KeyBased != nil:
+		credential["type"] = "key-based"
+		credential["username"] = device.KeyBased.Username
+		credential["key_id"] = device.KeyBased.KeyId
+	default:
+		return []interface{}{}
+	}
+	return []interface{}{credential}
+}
+
+// keepConfiguredNetconfPasswords replaces the passwords of a mount that are only known by their hash,
+// ie unchanged passwords read from state, with the ones configured on the controller so the hash
+// is never sent as the password
+func keepConfiguredNetconfPasswords(apiClient *client.Client, device *payload.Netconf) error {
+	hashed := func(password string) bool {
+		return strings.HasPrefix(password, passwordHashPrefix)
+	}
+	hashedLogin := device.LoginPassword != nil && hashed(device.LoginPassword.Password)
+	hashedUnencrypted := device.LoginPasswordUnencrypted != nil && hashed(device.LoginPasswordUnencrypted.Password)
+	if !hashed(device.Password) && !hashedLogin && !hashedUnencrypted {
+		return nil
+	}
+
+	bodyBytes, err := apiClient.GetNetconf(payload.NetconfMountURL(device.NodeId))
+	if err != nil {
+		return fmt.Errorf("reading the password of device %s to keep it: %w", device.NodeId, err)
+	}
+	configured, err := payload.ParseNetconfMountPayload(bodyBytes)
+	if err != nil {
+		return err
+	}
+
+	if hashed(device.Password) {
+		if hashPassword(configured.Password) != device.Password {
+			return fmt.Errorf("the password of device %s was changed outside of terraform, set it again", device.NodeId)
+		}
+		device.Password = configured.Password
+	}
+	changedCredentials := fmt.Errorf("the credentials of device %s were changed outside of terraform, set them again", device.NodeId)
+	// login-password credentials are encrypted by the controller, so only their presence is checked
+	if hashedLogin {
+		if configured.LoginPassword == nil {
+			return changedCredentials
+		}
+		device.LoginPassword.Password = configured.LoginPassword.Password
+	}
+	if hashedUnencrypted {
+		if configured.LoginPasswordUnencrypted == nil ||
+			hashPassword(configured.LoginPasswordUnencrypted.Password) != device.LoginPasswordUnencrypted.Password {
+			return changedCredentials
+		}
+		device.LoginPasswordUnencrypted.Password = configured.LoginPasswordUnencrypted.Password
+	}
+	return nil
+}
+
+// resolveNetconfPassword returns the password from the password, password_env or password_file attribute
+func resolveNetconfPassword(d resourceGetter) (string, error) {
+	if env := d.Get("password_env").(string); env != "" {
+		password, ok := os.LookupEnv(env)
+		if !ok {
+			return "", fmt.Errorf("password_env %s is not set", env)
+		}
+		return password, nil
+	}
+	if file := d.Get("password_file").(string); file != "" {
+		password, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("reading password_file: %w", err)
+		}
+		return strings.TrimRight(string(password), "\r\n"), nil
+	}
+	return d.Get("password").(string), nil
+}
+
+// resourceNetconfDevicePasswordDiff plans an update when the password hash on the controller
+// no longer matches the configured password, which also covers password_env and password_file
+func resourceNetconfDevicePasswordDiff(d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Get("credentials").([]interface{})) > 0 {
+		return nil
+	}
+	for _, key := range []string{"password", "password_env", "password_file"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+
+	password, err := resolveNetconfPassword(d)
+	if err != nil {
+		return err
+	}
+
+	if hash := hashPasswordState(password); hash != d.Get("password_hash").(string) {
+		return d.SetNew("password_hash", hash)
+	}
+	return nil
+}
+
+// resourceNetconfDeviceUpgradeV0 replaces the plaintext passwords of version 0 state with their hash
+func resourceNetconfDeviceUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if password, ok := rawState["password"].(string); ok && password != "" {
+		rawState["password"] = hashPasswordState(password)
+		rawState["password_hash"] = hashPasswordState(password)
+	}
+	if credentials, ok := rawState["credentials"].([]interface{}); ok {
+		for _, v := range credentials {
+			if credential, ok := v.(map[string]interface{}); ok {
+				if password, ok := credential["password"].(string); ok && password != "" {
+					credential["password"] = hashPasswordState(password)
+				}
+			}
+		}
+	}
+	return rawState, nil
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"net/http"
+	"qasimraz/terraform-provider-lsc-demo/api/payload"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testNetconfDevice returns the ResourceData of a new lsc_netconf_device with a create timeout
+func testNetconfDevice(timeout time.Duration) (*schema.Resource, *schema.ResourceData) {
+	r := resourceNetconfDevice()
+	r.Timeouts.Create = &timeout
+	d := r.Data(&terraform.InstanceState{Attributes: map[string]string{
+		"name":       "r1",
+		"ip_address": "192.0.2.1",
+		"port":       "830",
+		"username":   "admin",
+		"password":   "secret",
+	}})
+	d.MarkNewResource()
+	return r, d
+}
+
+func TestCreateNetconfDeviceWriteError(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	// New devices are posted to the netconf topology
+	topology := payload.ConfigPath().Child("network-topology:network-topology").Entry("topology", "topology-netconf")
+	controller.status[topology.String()] = http.StatusBadRequest
+
+	r, d := testNetconfDevice(time.Second)
+	if err := r.Create(d, controller.client()); err == nil {
+		t.Fatal("expected the error of mounting the device")
+	}
+	if d.Id() != "" {
+		t.Errorf("expected no id when the device wasn't mounted, got %s", d.Id())
+	}
+	if controller.request("GET", payload.NetconfMountURLOperational("r1")) != nil {
+		t.Error("expected no wait for a device that wasn't mounted")
+	}
+}
+
+func TestCreateNetconfDeviceWaitsForConnection(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	controller.reply(payload.NetconfMountURLOperational("r1"),
+		`{"node":[{"node-id":"r1","netconf-node-topology:connection-status":"connected"}]}`)
+
+	r, d := testNetconfDevice(time.Second)
+	if err := r.Create(d, controller.client()); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "r1" {
+		t.Errorf("expected id r1, got %s", d.Id())
+	}
+}
+
+func TestCreateNetconfDeviceTimeout(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	controller.reply(payload.NetconfMountURLOperational("r1"),
+		`{"node":[{"node-id":"r1","netconf-node-topology:connection-status":"connecting"}]}`)
+
+	r, d := testNetconfDevice(time.Second)
+	start := time.Now()
+	err := r.Create(d, controller.client())
+	if err == nil || !strings.Contains(err.Error(), "connecting") {
+		t.Fatalf("expected the device to time out connecting, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the wait to end with the create timeout, took %s", elapsed)
+	}
+	// The device is mounted, so it stays in state to be tainted
+	if d.Id() != "r1" {
+		t.Errorf("expected id r1 for the mounted device, got %q", d.Id())
+	}
+}
+
+// Updates that leave the password alone send the password configured on the controller,
+// never the hash kept in state
+func TestUpdateNetconfDeviceKeepsPassword(t *testing.T) {
+	cases := []struct {
+		name       string
+		state      map[string]string
+		config     map[string]interface{}
+		configured string
+		expected   string
+	}{
+		{
+			name: "password",
+			state: map[string]string{
+				"username":      "admin",
+				"password":      hashPassword("secret"),
+				"password_hash": hashPassword("secret"),
+			},
+			config:     map[string]interface{}{"username": "admin", "password": "secret"},
+			configured: `"netconf-node-topology:username":"admin","netconf-node-topology:password":"secret"`,
+			expected:   `"netconf-node-topology:password":"secret"`,
+		},
+		{
+			name: "login-password-unencrypted credentials",
+			state: map[string]string{
+				"credentials.#":          "1",
+				"credentials.0.type":     "login-password-unencrypted",
+				"credentials.0.username": "admin",
+				"credentials.0.password": hashPassword("secret"),
+			},
+			config: map[string]interface{}{"credentials": []interface{}{map[string]interface{}{
+				"type": "login-password-unencrypted", "username": "admin", "password": "secret",
+			}}},
+			configured: `"netconf-node-topology:login-password-unencrypted":{"netconf-node-topology:username":"admin","netconf-node-topology:password":"secret"}`,
+			expected:   `"netconf-node-topology:login-password-unencrypted":{"username":"admin","password":"secret"}`,
+		},
+		{
+			name: "login-password credentials",
+			state: map[string]string{
+				"credentials.#":          "1",
+				"credentials.0.type":     "login-password",
+				"credentials.0.username": "admin",
+				"credentials.0.password": hashPassword("secret"),
+			},
+			config: map[string]interface{}{"credentials": []interface{}{map[string]interface{}{
+				"type": "login-password", "username": "admin", "password": "secret",
+			}}},
+			configured: `"netconf-node-topology:login-password":{"netconf-node-topology:username":"admin","netconf-node-topology:password":"encrypted"}`,
+			expected:   `"netconf-node-topology:login-password":{"username":"admin","password":"encrypted"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := newTestController()
+			defer controller.close()
+			controller.reply(payload.NetconfMountURL("r1"), `{"node":[{"node-id":"r1","netconf-node-topology:host":"192.0.2.1",`+
+				`"netconf-node-topology:port":830,"netconf-node-topology:keepalive-delay":120,`+c.configured+`}]}`)
+			controller.reply(payload.NetconfMountURLOperational("r1"),
+				`{"node":[{"node-id":"r1","netconf-node-topology:connection-status":"connected"}]}`)
+
+			r := resourceNetconfDevice()
+			state := &terraform.InstanceState{ID: "r1", Attributes: map[string]string{
+				"id":              "r1",
+				"name":            "r1",
+				"ip_address":      "192.0.2.1",
+				"port":            "830",
+				"keepalive_delay": "120",
+			}}
+			for key, value := range c.state {
+				state.Attributes[key] = value
+			}
+			config := map[string]interface{}{"name": "r1", "ip_address": "192.0.2.1", "port": 830, "keepalive_delay": 60}
+			for key, value := range c.config {
+				config[key] = value
+			}
+
+			apiClient := controller.client()
+			diff, err := r.Diff(state, terraform.NewResourceConfigRaw(config), apiClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := diff.Attributes["keepalive_delay"]; !ok || len(diff.Attributes) != 1 {
+				t.Fatalf("expected only keepalive_delay to change, got %v", diff.Attributes)
+			}
+			if _, err := r.Apply(state, diff, apiClient); err != nil {
+				t.Fatal(err)
+			}
+
+			put := controller.request("PUT", payload.NetconfMountURL("r1"))
+			if put == nil {
+				t.Fatalf("expected the mount to be updated, got writes %v", controller.writes())
+			}
+			if strings.Contains(put.body, passwordHashPrefix) || !strings.Contains(put.body, c.expected) {
+				t.Errorf("expected the update to send %s, got %s", c.expected, put.body)
+			}
+		})
+	}
+}
+
+func TestUpdateNetconfDevicePasswordChangedOutside(t *testing.T) {
+	controller := newTestController()
+	defer controller.close()
+	controller.reply(payload.NetconfMountURL("r1"), `{"node":[{"node-id":"r1","netconf-node-topology:host":"192.0.2.1",`+
+		`"netconf-node-topology:port":830,"netconf-node-topology:username":"admin","netconf-node-topology:password":"other"}]}`)
+
+	r := resourceNetconfDevice()
+	d := r.Data(&terraform.InstanceState{ID: "r1", Attributes: map[string]string{
+		"id":         "r1",
+		"name":       "r1",
+		"ip_address": "192.0.2.1",
+		"port":       "830",
+		"username":   "admin",
+		"password":   hashPassword("secret"),
+	}})
+	if err := r.Update(d, controller.client()); err == nil || !strings.Contains(err.Error(), "changed outside of terraform") {
+		t.Fatalf("expected the password changed outside of terraform to fail the update, got %v", err)
+	}
+	if writes := controller.writes(); len(writes) != 0 {
+		t.Errorf("expected no write, got %v", writes)
+	}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"qasimraz/terraform-provider-lsc-demo/api/client"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// versionsKey is the attribute the versions of a resource's configuration are kept in, by url
+const versionsKey = "versions"
+
+// trackVersions adds the attribute readNetconf keeps the versions of the configuration of a
+// resource in. The versions are planned as unknown when the resource changes, as its writes
+// change them, so the writes take the versions from the prior state
+func trackVersions(r *schema.Resource) {
+	r.Schema[versionsKey] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "ETag, or Last-Modified when there is none, of the configuration at each url when it was last read, sent as a precondition when it is changed or deleted",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(d *schema.ResourceDiff, m interface{}) error {
+		if customizeDiff != nil {
+			if err := customizeDiff(d, m); err != nil {
+				return err
+			}
+		}
+		if d.Id() != "" && len(d.GetChangedKeysPrefix("")) > 0 {
+			return d.SetNewComputed(versionsKey)
+		}
+		return nil
+	}
+}
+
+// stateVersion returns the version of the configuration at a url the resource last read, resources
+// that didn't read it get an empty version so their writes are unconditional
+func stateVersion(d *schema.ResourceData, url string) client.Version {
+	old, _ := d.GetChange(versionsKey)
+	versions, _ := old.(map[string]interface{})
+	version, _ := versions[url].(string)
+	if version == "" {
+		return client.Version{}
+	}
+	return client.ParseVersion(version)
+}
+
+// setStateVersion keeps the version of the configuration at a url the resource read
+func setStateVersion(d *schema.ResourceData, url string, version client.Version) {
+	versions := map[string]interface{}{}
+	if current, ok := d.Get(versionsKey).(map[string]interface{}); ok {
+		for key, value := range current {
+			versions[key] = value
+		}
+	}
+	if version.IsZero() {
+		delete(versions, url)
+	} else {
+		versions[url] = version.String()
+	}
+	d.Set(versionsKey, versions)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// generator writes the Go types of data nodes
+type generator struct {
+	state   bool            // config false nodes are generated too
+	taken   map[string]bool // identifiers declared in the package
+	structs bytes.Buffer
+	enums   map[string]*enumType // enumerations by typedef or by the leaf they are inline in
+	modules map[*module]bool     // modules the generated nodes come from
+	json    bool                 // anydata nodes need encoding/json
+}
+
+// enumType is a string type with a constant for each value of an enumeration
+type enumType struct {
+	name        string
+	description string
+	values      []string
+}
+
+// packageIdentifiers returns the top-level identifiers of the package in a directory, except
+// those of the file that is generated
+func packageIdentifiers(dir string, out string) (map[string]bool, error) {
+	taken := map[string]bool{}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if filepath.Base(path) == filepath.Base(out) || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					taken[decl.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						taken[spec.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							taken[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return taken, nil
+}
+
+// root generates the struct of a root node and the types of its descendants
+func (g *generator) root(path string, name string, n *node) error {
+	if g.taken[name] {
+		return fmt.Errorf("%s is already declared in the package", name)
+	}
+	g.taken[name] = true
+	g.modules[n.module] = true
+	g.structType(name, "/"+strings.Trim(path, "/"), n)
+	return nil
+}
+
+// structType writes the struct of a container or list entry, followed by the types of its children
+func (g *generator) structType(name string, path string, n *node) {
+	fmt.Fprintf(&g.structs, "// %s is %s", name, path)
+	if description := firstSentence(n.description); description != "" {
+		fmt.Fprintf(&g.structs, "\n//\n// %s", description)
+	}
+	fmt.Fprintf(&g.structs, "\ntype %s struct {\n", name)
+
+	var nested []func()
+	fieldNames := map[string]bool{}
+	for _, child := range n.children {
+		if !g.included(child) {
+			continue
+		}
+		g.modules[child.module] = true
+
+		fieldName := identifier(child.name)
+		if fieldNames[fieldName] {
+			fieldName = identifier(shortName(child.module.name)) + fieldName
+		}
+		fieldNames[fieldName] = true
+
+		// Members are qualified with their module when it differs from their parent's, as in RFC 7951
+		key := child.name
+		if child.module != n.module {
+			key = child.module.name + ":" + child.name
+		}
+		tag := key + ",omitempty"
+		if n.kind == "list" && child.module == n.module && contains(n.keys, child.name) {
+			tag = key
+		}
+
+		goType := ""
+		switch child.kind {
+		case "container", "list":
+			childType := g.typeName(name + fieldName)
+			childPath := path + "/" + child.name
+			child := child
+			nested = append(nested, func() { g.structType(childType, childPath, child) })
+			goType = "*" + childType
+			if child.kind == "list" {
+				goType = "[]" + childType
+			}
+		case "leaf":
+			goType = g.leafType(child, name+fieldName)
+			// false and 0 are values of their own when the default is another one
+			if child.defaulted && (goType == "bool" || goType == "float64" || strings.Contains(goType, "int")) {
+				goType = "*" + goType
+			}
+		case "leaf-list":
+			goType = "[]" + strings.TrimPrefix(g.leafType(child, name+fieldName), "*")
+		case "anydata":
+			goType = "json.RawMessage"
+			g.json = true
+		}
+
+		tags := fmt.Sprintf("json:%q", tag)
+		if child.typ != nil && child.typ.restriction != "" {
+			tags += fmt.Sprintf(" yang:%q", child.typ.restriction+"="+child.typ.bounds)
+		}
+		if description := firstSentence(child.description); description != "" {
+			fmt.Fprintf(&g.structs, "\t// %s\n", description)
+		}
+		fmt.Fprintf(&g.structs, "\t%s %s `%s`\n", fieldName, goType, tags)
+	}
+	g.structs.WriteString("}\n\n")
+
+	for _, generate := range nested {
+		generate()
+	}
+}
+
+// included reports whether a node is generated, containers and lists need a generated descendant
+func (g *generator) included(n *node) bool {
+	if !n.config && !g.state {
+		return false
+	}
+	if n.kind != "container" && n.kind != "list" {
+		return true
+	}
+	for _, child := range n.children {
+		if g.included(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// leafType returns the Go type of a leaf, enumerations get a type of their own
+func (g *generator) leafType(n *node, inlineName string) string {
+	switch n.typ.base {
+	case "boolean":
+		return "bool"
+	case "empty":
+		return "*Empty"
+	case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+		return n.typ.base
+	case "decimal64":
+		return "float64"
+	case "enumeration":
+		key, name, description := inlineName, inlineName, fmt.Sprintf("values of %s", n.name)
+		if n.typ.enumName != "" {
+			key = n.typ.enumModule.name + ":" + n.typ.enumName
+			name = identifier(n.typ.enumName)
+			description = fmt.Sprintf("values of %s of %s", n.typ.enumName, n.typ.enumModule.name)
+		}
+		if enum, ok := g.enums[key]; ok {
+			return enum.name
+		}
+		if g.taken[name] && n.typ.enumName != "" {
+			name = identifier(shortName(n.typ.enumModule.name)) + name
+		}
+		enum := &enumType{name: g.typeName(name), description: description, values: n.typ.enums}
+		g.enums[key] = enum
+		return enum.name
+	}
+	// Unions, identities, leafrefs, bits and binaries are strings in json
+	return "string"
+}
+
+// typeName returns a name that isn't declared in the package yet and takes it
+func (g *generator) typeName(name string) string {
+	unique := name
+	for i := 2; g.taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.taken[unique] = true
+	return unique
+}
+
+// namespacesName is the map of the XML namespaces of the modules the types are generated from
+const namespacesName = "yangModuleNamespaces"
+
+// source returns the generated file
+func (g *generator) source(pkg string) []byte {
+	var modules []string
+	var generated []*module
+	for m := range g.modules {
+		name := m.name
+		if m.revision != "" {
+			name += "@" + m.revision
+		}
+		modules = append(modules, name)
+		generated = append(generated, m)
+	}
+	sort.Strings(modules)
+	sort.Slice(generated, func(i, j int) bool { return generated[i].name < generated[j].name })
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by yanggen from YANG modules. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if g.json {
+		src.WriteString("import \"encoding/json\"\n\n")
+	}
+	src.WriteString("// Generated from\n")
+	for _, m := range modules {
+		fmt.Fprintf(&src, "//   %s\n", m)
+	}
+	src.WriteString("\n")
+	src.Write(g.structs.Bytes())
+
+	var keys []string
+	for key := range g.enums {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return g.enums[keys[i]].name < g.enums[keys[j]].name })
+	for _, key := range keys {
+		enum := g.enums[key]
+		fmt.Fprintf(&src, "// %s are the %s\ntype %s string\n\n", enum.name, enum.description, enum.name)
+		fmt.Fprintf(&src, "// Values of %s\nconst (\n", enum.name)
+		for _, value := range enum.values {
+			fmt.Fprintf(&src, "\t%s %s = %q\n", g.typeName(enum.name+camelCase(value)), enum.name, value)
+		}
+		values := g.typeName(enum.name + "Values")
+		fmt.Fprintf(&src, ")\n\n// %s are the values of %s, ie for validation.StringInSlice\n", values, enum.name)
+		fmt.Fprintf(&src, "var %s = []string{", values)
+		for _, value := range enum.values {
+			fmt.Fprintf(&src, "%q, ", value)
+		}
+		src.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(&src, "// %s are the XML namespaces of the modules the types are generated from\n", namespacesName)
+	fmt.Fprintf(&src, "var %s = map[string]string{\n", namespacesName)
+	for _, m := range generated {
+		fmt.Fprintf(&src, "\t%q: %q,\n", m.name, m.namespace)
+	}
+	src.WriteString("}\n")
+	return src.Bytes()
+}
+
+// identifier turns a YANG name into an exported Go identifier, ie interface-name is InterfaceName
+func identifier(name string) string {
+	id := camelCase(name)
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		return "N" + id
+	}
+	return id
+}
+
+// camelCase joins the words of a YANG name with their first letter in upper case, the result
+// starts with a digit when the name does, ie for enum values that follow the name of their type
+func camelCase(name string) string {
+	var id strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		id.WriteRune(r)
+	}
+	return id.String()
+}
+
+// shortName returns a module name without the prefix of its vendor
+func shortName(moduleName string) string {
+	for _, prefix := range []string{"Cisco-IOS-XR-", "openconfig-", "ietf-"} {
+		moduleName = strings.TrimPrefix(moduleName, prefix)
+	}
+	return moduleName
+}
+
+// firstSentence returns the first sentence of a description on a single line
+func firstSentence(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if i := strings.Index(description, ". "); i >= 0 {
+		description = description[:i+1]
+	}
+	return description
+}
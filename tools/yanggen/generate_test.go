@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the generated source")
+
+// generateExample generates the device of the example modules in testdata and returns the source
+func generateExample(t *testing.T, state bool) string {
+	dir, err := ioutil.TempDir("", "yanggen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "example_types.go")
+	modules := []string{"example", "example-augment"}
+	roots := []string{"/example:devices/device=Device"}
+	if err := generate("testdata", out, "example", state, modules, roots); err != nil {
+		t.Fatal(err)
+	}
+	src, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(src)
+}
+
+func TestGenerateGolden(t *testing.T) {
+	src := generateExample(t, false)
+
+	golden := filepath.Join("testdata", "example_types.go.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal([]byte(src), expected) {
+		t.Errorf("generated source differs from %s, run go test -update if the change is expected:\n%s", golden, src)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src := generateExample(t, false)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"list keys have no omitempty", "Name string `json:\"name\" yang:\"length=1..64\"`"},
+		{"leaves have omitempty", "Enabled bool `json:\"enabled,omitempty\"`"},
+		{"booleans with a default are pointers", "Negotiate *bool `json:\"negotiate,omitempty\"`"},
+		{"numbers with a default are pointers", "Keepalive *uint32 `json:\"keepalive,omitempty\"`"},
+		{"strings with a default are values", "Role string `json:\"role,omitempty\"`"},
+		{"ranges are kept", "Port uint16 `json:\"port,omitempty\" yang:\"range=1..65535\"`"},
+		{"ranges of typedefs are kept", "Mtu uint32 `json:\"mtu,omitempty\" yang:\"range=64..9216\"`"},
+		{"empty leaves", "Shutdown *Empty `json:\"shutdown,omitempty\"`"},
+		{"leaf-lists", "Tags []string `json:\"tags,omitempty\"`"},
+		{"cases are in their parent", "Ipv4Address string `json:\"ipv4-address,omitempty\"`"},
+		{"augments are qualified with their module", "Vrf string `json:\"example-augment:vrf,omitempty\"`"},
+		{"augmented containers are qualified with their module", "Qos *DeviceQos `json:\"example-augment:qos,omitempty\"`"},
+		{"children of augmented containers are not qualified", "Policy string `json:\"policy,omitempty\"`"},
+		{"inline enumerations are named by their leaf", "Mode DeviceMode `json:\"mode,omitempty\"`"},
+		{"enumeration typedefs are named by the typedef", "Speed Speed `json:\"speed,omitempty\"`"},
+		{"inline enumeration values", "DeviceModeAccess DeviceMode = \"access\""},
+		{"enumeration typedef values", "Speed10g Speed = \"10g\""},
+		{"values of enumerations", "var SpeedValues = []string{\"10g\", \"100g\", \"auto\"}"},
+	}
+	// Fields are compared without the alignment of gofmt
+	fields := strings.Join(strings.Fields(src), " ")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !strings.Contains(fields, test.expected) {
+				t.Errorf("expected %s in:\n%s", test.expected, src)
+			}
+		})
+	}
+
+	if strings.Contains(src, "Counters") {
+		t.Errorf("expected config false nodes to be left out:\n%s", src)
+	}
+}
+
+func TestGenerateState(t *testing.T) {
+	src := generateExample(t, true)
+	fields := strings.Join(strings.Fields(src), " ")
+	if !strings.Contains(fields, "Counters *DeviceCounters `json:\"counters,omitempty\"`") {
+		t.Errorf("expected config false nodes with -state:\n%s", src)
+	}
+}
+
+func TestGenerateUnknownRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yanggen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = generate("testdata", filepath.Join(dir, "example_types.go"), "example", false, []string{"example"}, []string{"/example:devices/switch=Switch"})
+	if err == nil || !strings.Contains(err.Error(), "no node switch") {
+		t.Errorf("expected an error for a root that isn't in the modules, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+// yanggen generates Go structs for YANG data nodes. Their json keys, enumerations and ranges
+// are taken from the modules the controller and the devices implement instead of from sample
+// replies. Modules are read from a local directory, named module.yang or module@revision.yang,
+// and the modules they import must be there too.
+//
+// Usage:
+//
+//	yanggen -dir yang -out yang_types.go -package payload \
+//		-module Cisco-IOS-XR-ifmgr-cfg -module 'openconfig-*' \
+//		-root /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration=InterfaceConfiguration
+//
+// Nodes of the selected modules, and the augments between them, are generated below each root
+// as a struct named by the root. Leaves are generated as values with omitempty, except list keys,
+// and their range or length is kept in a yang tag, ie `yang:"range=64..65535"`. Boolean and numeric
+// leaves with a default are pointers, so false and 0 are sent when they are set. The XML namespaces
+// of the modules are generated in the yangModuleNamespaces map. The package has to declare an
+// Empty type for leaves of type empty.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stringList is a flag that can be given more than once
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("yanggen: ")
+
+	var modules, roots stringList
+	dir := flag.String("dir", "yang", "directory of the YANG modules")
+	out := flag.String("out", "", "Go file to generate")
+	pkg := flag.String("package", "", "package of the generated file, the directory name by default")
+	state := flag.Bool("state", false, "generate config false nodes too")
+	flag.Var(&modules, "module", "module whose nodes and augments are generated, a pattern like openconfig-* selects several")
+	flag.Var(&roots, "root", "path=Type of a node to generate a struct for, the path is qualified with module names as in urls")
+	flag.Parse()
+
+	if *out == "" || len(modules) == 0 || len(roots) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *pkg == "" {
+		abs, err := filepath.Abs(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*pkg = filepath.Base(filepath.Dir(abs))
+	}
+
+	if err := generate(*dir, *out, *pkg, *state, modules, roots); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(dir string, out string, pkg string, state bool, patterns []string, roots []string) error {
+	s, err := newSchema(dir)
+	if err != nil {
+		return fmt.Errorf("%s, copy the modules from github.com/YangModels/yang or a device's schema list into it", err)
+	}
+
+	var names []string
+	for _, pattern := range patterns {
+		matched, err := s.moduleNames(pattern)
+		if err != nil {
+			return err
+		}
+		for _, name := range matched {
+			if !contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if err := s.load(names); err != nil {
+		return err
+	}
+
+	taken, err := packageIdentifiers(filepath.Dir(out), out)
+	if err != nil {
+		return err
+	}
+	if taken[namespacesName] {
+		return fmt.Errorf("%s is already declared in the package", namespacesName)
+	}
+	taken[namespacesName] = true
+	g := &generator{state: state, taken: taken, enums: map[string]*enumType{}, modules: map[*module]bool{}}
+	for _, root := range roots {
+		i := strings.LastIndex(root, "=")
+		if i < 0 {
+			return fmt.Errorf("root %s is not path=Type", root)
+		}
+		path, name := root[:i], root[i+1:]
+		n, err := s.root(path)
+		if err != nil {
+			return err
+		}
+		if err := g.root(path, name, n); err != nil {
+			return err
+		}
+	}
+
+	src := g.source(pkg)
+	formatted, err := format.Source(src)
+	if err != nil {
+		// The unformatted source is kept to find what was generated wrong
+		ioutil.WriteFile(out, src, 0644)
+		return fmt.Errorf("generated %s doesn't parse: %s", out, err)
+	}
+	return ioutil.WriteFile(out, formatted, 0644)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// statement is a YANG statement with its argument and substatements
+type statement struct {
+	keyword string
+	arg     string
+	subs    []*statement
+	file    string
+	line    int
+}
+
+// sub returns the first substatement with a keyword, nil when there is none
+func (s *statement) sub(keyword string) *statement {
+	for _, sub := range s.subs {
+		if sub.keyword == keyword {
+			return sub
+		}
+	}
+	return nil
+}
+
+// subArg returns the argument of the first substatement with a keyword
+func (s *statement) subArg(keyword string) string {
+	if sub := s.sub(keyword); sub != nil {
+		return sub.arg
+	}
+	return ""
+}
+
+func (s *statement) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s %s: %s", s.file, s.line, s.keyword, s.arg, fmt.Sprintf(format, args...))
+}
+
+// parseFile parses a YANG file, which holds a single module or submodule
+func parseFile(path string) (*statement, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &lexer{src: string(data), file: path, line: 1}
+	statements, err := l.statements(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) != 1 || (statements[0].keyword != "module" && statements[0].keyword != "submodule") {
+		return nil, fmt.Errorf("%s: expected a single module or submodule", path)
+	}
+	return statements[0], nil
+}
+
+// lexer splits YANG source into statements
+type lexer struct {
+	src  string
+	pos  int
+	file string
+	line int
+}
+
+// lexeme is a keyword, an argument or one of ; { }
+type lexeme struct {
+	text   string
+	quoted bool
+	line   int
+}
+
+func (t lexeme) is(delimiter string) bool {
+	return !t.quoted && t.text == delimiter
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", l.file, l.line, fmt.Sprintf(format, args...))
+}
+
+// statements parses statements up to the end of the file, or up to the closing brace of a block
+func (l *lexer) statements(block bool) ([]*statement, error) {
+	var statements []*statement
+	for {
+		keyword, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case keyword.text == "" && !keyword.quoted:
+			if block {
+				return nil, l.errorf("missing }")
+			}
+			return statements, nil
+		case keyword.is("}"):
+			if !block {
+				return nil, l.errorf("unexpected }")
+			}
+			return statements, nil
+		case keyword.is(";"), keyword.is("{"):
+			return nil, l.errorf("unexpected %s", keyword.text)
+		}
+
+		s := &statement{keyword: keyword.text, file: l.file, line: keyword.line}
+		end, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		if !end.is(";") && !end.is("{") {
+			s.arg = end.text
+			// Quoted strings are concatenated with +
+			for {
+				saved, savedLine := l.pos, l.line
+				plus, err := l.next()
+				if err != nil {
+					return nil, err
+				}
+				if !plus.is("+") {
+					l.pos, l.line = saved, savedLine
+					break
+				}
+				part, err := l.next()
+				if err != nil {
+					return nil, err
+				}
+				s.arg += part.text
+			}
+			if end, err = l.next(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case end.is(";"):
+		case end.is("{"):
+			if s.subs, err = l.statements(true); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, l.errorf("expected ; or { after %s", s.keyword)
+		}
+		statements = append(statements, s)
+	}
+}
+
+// next returns the next lexeme, an empty unquoted lexeme at the end of the file
+func (l *lexer) next() (lexeme, error) {
+	if err := l.skipSpace(); err != nil {
+		return lexeme{}, err
+	}
+	if l.pos >= len(l.src) {
+		return lexeme{}, nil
+	}
+
+	line := l.line
+	switch c := l.src[l.pos]; c {
+	case ';', '{', '}':
+		l.pos++
+		return lexeme{text: string(c), line: line}, nil
+	case '"':
+		text, err := l.doubleQuoted()
+		return lexeme{text: text, quoted: true, line: line}, err
+	case '\'':
+		end := strings.IndexByte(l.src[l.pos+1:], '\'')
+		if end < 0 {
+			return lexeme{}, l.errorf("unterminated string")
+		}
+		text := l.src[l.pos+1 : l.pos+1+end]
+		l.line += strings.Count(text, "\n")
+		l.pos += end + 2
+		return lexeme{text: text, quoted: true, line: line}, nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.src) && !strings.ContainsRune(" \t\r\n;{}", rune(l.src[l.pos])) {
+		l.pos++
+	}
+	return lexeme{text: l.src[start:l.pos], line: line}, nil
+}
+
+// doubleQuoted reads a double quoted string and replaces its escapes
+func (l *lexer) doubleQuoted() (string, error) {
+	var text strings.Builder
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return text.String(), nil
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.src) {
+				break
+			}
+			switch l.src[l.pos] {
+			case 'n':
+				text.WriteByte('\n')
+			case 't':
+				text.WriteByte('\t')
+			default:
+				text.WriteByte(l.src[l.pos])
+			}
+			continue
+		case '\n':
+			l.line++
+		}
+		text.WriteByte(c)
+	}
+	return "", l.errorf("unterminated string")
+}
+
+// skipSpace skips whitespace and comments
+func (l *lexer) skipSpace() error {
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\n':
+			l.line++
+			l.pos++
+		case strings.ContainsRune(" \t\r", rune(l.src[l.pos])):
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			end := strings.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf("unterminated comment")
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// module is a parsed YANG module, the bodies of its submodules are merged into it
+type module struct {
+	name      string
+	namespace string
+	prefix    string
+	revision  string
+	imports   map[string]string // module names by prefix
+	stmt      *statement
+	top       []*node
+}
+
+// node is a data node of the schema tree
+type node struct {
+	name        string
+	module      *module // namespace of the node, the module that defined or augmented it
+	kind        string  // container, list, leaf, leaf-list or anydata
+	keys        []string
+	typ         *leafType
+	defaulted   bool // the leaf or its typedef has a default
+	description string
+	config      bool
+	children    []*node
+}
+
+// findNode returns the node with a name, in a module when it isn't empty
+func findNode(nodes []*node, name string, moduleName string) *node {
+	for _, n := range nodes {
+		if n.name == name && (moduleName == "" || n.module.name == moduleName) {
+			return n
+		}
+	}
+	return nil
+}
+
+// leafType is the resolved type of a leaf, typedefs are followed down to a built-in type
+type leafType struct {
+	base        string   // built-in type
+	enumName    string   // typedef of an enumeration, empty for inline enumerations
+	enumModule  *module  // module of the typedef
+	enums       []string // values of an enumeration
+	restriction string   // range or length
+	bounds      string   // argument of the restriction
+	defaulted   bool     // a typedef gives a default
+}
+
+var builtinTypes = map[string]bool{
+	"binary": true, "bits": true, "boolean": true, "decimal64": true, "empty": true,
+	"enumeration": true, "identityref": true, "instance-identifier": true, "int8": true,
+	"int16": true, "int32": true, "int64": true, "leafref": true, "string": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "union": true,
+}
+
+// scope is where typedefs and groupings are looked up, nested definitions hide outer ones
+type scope struct {
+	module *module
+	stmt   *statement
+	parent *scope
+}
+
+// schema loads modules from a directory on demand, modules are only parsed when imported
+type schema struct {
+	dir     string
+	files   map[string]string // file of the latest revision of each module
+	modules map[string]*module
+	depth   int // nesting of groupings, to stop ones that use themselves
+}
+
+func newSchema(dir string) (*schema, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yang"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no YANG modules in %s", dir)
+	}
+
+	// Files are named module.yang or module@revision.yang, later revisions sort last
+	sort.Strings(paths)
+	files := map[string]string{}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".yang")
+		if i := strings.Index(name, "@"); i >= 0 {
+			name = name[:i]
+		}
+		files[name] = path
+	}
+	return &schema{dir: dir, files: files, modules: map[string]*module{}}, nil
+}
+
+// moduleNames returns the modules in the directory matching a pattern, ie openconfig-*,
+// submodules are left out as they are included by their module
+func (s *schema) moduleNames(pattern string) ([]string, error) {
+	var names []string
+	for name, path := range s.files {
+		matched, err := filepath.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+		stmt, err := parseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if stmt.keyword == "module" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no module %s in %s", pattern, s.dir)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// module returns a parsed module, its submodules are included into it
+func (s *schema) module(name string) (*module, error) {
+	if m, ok := s.modules[name]; ok {
+		return m, nil
+	}
+	path, ok := s.files[name]
+	if !ok {
+		return nil, fmt.Errorf("module %s is not in %s", name, s.dir)
+	}
+	stmt, err := parseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.keyword != "module" {
+		return nil, stmt.errorf("is a submodule, not a module")
+	}
+
+	m := &module{
+		name:      stmt.arg,
+		namespace: stmt.subArg("namespace"),
+		prefix:    stmt.subArg("prefix"),
+		imports:   map[string]string{},
+		stmt:      stmt,
+	}
+	if revision := stmt.sub("revision"); revision != nil {
+		m.revision = revision.arg
+	}
+	m.imports[m.prefix] = m.name
+	s.addImports(m, stmt)
+
+	for _, include := range stmt.subs {
+		if include.keyword != "include" {
+			continue
+		}
+		path, ok := s.files[include.arg]
+		if !ok {
+			return nil, include.errorf("submodule is not in %s", s.dir)
+		}
+		sub, err := parseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		// The prefix of belongs-to refers to the module itself
+		if belongsTo := sub.sub("belongs-to"); belongsTo != nil {
+			m.imports[belongsTo.subArg("prefix")] = m.name
+		}
+		s.addImports(m, sub)
+		stmt.subs = append(stmt.subs, sub.subs...)
+	}
+
+	s.modules[name] = m
+	return m, nil
+}
+
+func (s *schema) addImports(m *module, stmt *statement) {
+	for _, sub := range stmt.subs {
+		if sub.keyword == "import" {
+			m.imports[sub.subArg("prefix")] = sub.arg
+		}
+	}
+}
+
+// split splits a prefixed name and returns the module the prefix refers to in a module
+func (s *schema) split(name string, m *module) (*module, string, error) {
+	i := strings.Index(name, ":")
+	if i < 0 {
+		return m, name, nil
+	}
+	moduleName, ok := m.imports[name[:i]]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown prefix %s in %s of module %s", name[:i], name, m.name)
+	}
+	target, err := s.module(moduleName)
+	return target, name[i+1:], err
+}
+
+// definition finds a typedef or grouping by its name in a scope and returns the scope it is defined in
+func (s *schema) definition(keyword string, name string, sc *scope) (*statement, *scope, error) {
+	target, local, err := s.split(name, sc.module)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target != sc.module {
+		sc = &scope{module: target, stmt: target.stmt}
+	}
+	for ; sc != nil; sc = sc.parent {
+		for _, sub := range sc.stmt.subs {
+			if sub.keyword == keyword && sub.arg == local {
+				return sub, &scope{module: sc.module, stmt: sub, parent: sc}, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("%s %s not found in module %s", keyword, name, target.name)
+}
+
+// resolveType follows the typedefs of a type down to its built-in type
+func (s *schema) resolveType(typ *statement, sc *scope) (*leafType, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("leaf without a type in module %s", sc.module.name)
+	}
+
+	var resolved leafType
+	if builtinTypes[typ.arg] {
+		resolved.base = typ.arg
+	} else {
+		typedef, typedefScope, err := s.definition("typedef", typ.arg, sc)
+		if err != nil {
+			return nil, typ.errorf("%s", err)
+		}
+		base, err := s.resolveType(typedef.sub("type"), typedefScope)
+		if err != nil {
+			return nil, err
+		}
+		resolved = *base
+		if typedef.sub("default") != nil {
+			resolved.defaulted = true
+		}
+		if resolved.base == "enumeration" && resolved.enumName == "" {
+			resolved.enumName = typedef.arg
+			resolved.enumModule = typedefScope.module
+		}
+	}
+
+	// Enums of a derived type restrict the values of its typedef
+	var enums []string
+	for _, sub := range typ.subs {
+		switch sub.keyword {
+		case "enum":
+			enums = append(enums, sub.arg)
+		case "range", "length":
+			resolved.restriction = sub.keyword
+			resolved.bounds = strings.Join(strings.Fields(sub.arg), "")
+		}
+	}
+	if len(enums) > 0 {
+		resolved.enums = enums
+	}
+	return &resolved, nil
+}
+
+// dataNodes builds the data nodes of statements, nodes take the namespace of the module that
+// uses a grouping or augments a node rather than the one that defines the grouping
+func (s *schema) dataNodes(stmts []*statement, sc *scope, namespace *module, config bool) ([]*node, error) {
+	s.depth++
+	defer func() { s.depth-- }()
+	if s.depth > 64 {
+		return nil, fmt.Errorf("groupings of module %s nest too deep, does one use itself?", sc.module.name)
+	}
+
+	var nodes []*node
+	for _, stmt := range stmts {
+		inner := &scope{module: sc.module, stmt: stmt, parent: sc}
+		switch stmt.keyword {
+		case "container", "list", "leaf", "leaf-list", "anydata", "anyxml":
+			n := &node{
+				name:        stmt.arg,
+				module:      namespace,
+				kind:        stmt.keyword,
+				keys:        strings.Fields(stmt.subArg("key")),
+				description: stmt.subArg("description"),
+				config:      config && stmt.subArg("config") != "false",
+			}
+			if n.kind == "anyxml" {
+				n.kind = "anydata"
+			}
+			if n.kind == "leaf" || n.kind == "leaf-list" {
+				typ, err := s.resolveType(stmt.sub("type"), sc)
+				if err != nil {
+					return nil, err
+				}
+				n.typ = typ
+				n.defaulted = typ.defaulted || stmt.sub("default") != nil
+			}
+			children, err := s.dataNodes(stmt.subs, inner, namespace, n.config)
+			if err != nil {
+				return nil, err
+			}
+			n.children = children
+			nodes = append(nodes, n)
+		case "choice", "case":
+			// Choices and cases aren't data nodes, their nodes are in the parent
+			children, err := s.dataNodes(stmt.subs, inner, namespace, config && stmt.subArg("config") != "false")
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, children...)
+		case "uses":
+			grouping, groupingScope, err := s.definition("grouping", stmt.arg, sc)
+			if err != nil {
+				return nil, stmt.errorf("%s", err)
+			}
+			children, err := s.dataNodes(grouping.subs, groupingScope, namespace, config)
+			if err != nil {
+				return nil, err
+			}
+			for _, augment := range stmt.subs {
+				if augment.keyword != "augment" {
+					continue
+				}
+				target := s.descendant(children, augment.arg, sc.module)
+				if target == nil {
+					return nil, augment.errorf("target not found in grouping %s", stmt.arg)
+				}
+				added, err := s.dataNodes(augment.subs, &scope{module: sc.module, stmt: augment, parent: sc}, namespace, target.config)
+				if err != nil {
+					return nil, err
+				}
+				target.children = append(target.children, added...)
+			}
+			nodes = append(nodes, children...)
+		}
+	}
+	return nodes, nil
+}
+
+// descendant finds a node by a schema path relative to nodes, ie a:b/c
+func (s *schema) descendant(nodes []*node, path string, m *module) *node {
+	var found *node
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		target, name, err := s.split(segment, m)
+		if err != nil {
+			return nil
+		}
+		moduleName := ""
+		if strings.Contains(segment, ":") {
+			moduleName = target.name
+		}
+		if found = findNode(nodes, name, moduleName); found == nil {
+			return nil
+		}
+		nodes = found.children
+	}
+	return found
+}
+
+// load builds the data tree of modules and applies their augments to each other
+func (s *schema) load(names []string) error {
+	var modules []*module
+	for _, name := range names {
+		m, err := s.module(name)
+		if err != nil {
+			return err
+		}
+		top, err := s.dataNodes(m.stmt.subs, &scope{module: m, stmt: m.stmt}, m, true)
+		if err != nil {
+			return err
+		}
+		m.top = top
+		modules = append(modules, m)
+	}
+
+	type augment struct {
+		module *module
+		stmt   *statement
+	}
+	var pending []augment
+	for _, m := range modules {
+		for _, stmt := range m.stmt.subs {
+			if stmt.keyword == "augment" {
+				pending = append(pending, augment{m, stmt})
+			}
+		}
+	}
+
+	// Augments can target nodes added by other augments, so they are applied until none applies
+	for applied := true; applied; {
+		applied = false
+		var remaining []augment
+		for _, a := range pending {
+			target := s.augmentTarget(a.stmt.arg, a.module, names)
+			if target == nil {
+				remaining = append(remaining, a)
+				continue
+			}
+			children, err := s.dataNodes(a.stmt.subs, &scope{module: a.module, stmt: a.stmt, parent: &scope{module: a.module, stmt: a.module.stmt}}, a.module, target.config)
+			if err != nil {
+				return err
+			}
+			target.children = append(target.children, children...)
+			applied = true
+		}
+		pending = remaining
+	}
+
+	// Augments of modules that weren't selected are left out with the nodes they target
+	for _, a := range pending {
+		log.Printf("skipping augment %s of %s, its target is not in the selected modules", a.stmt.arg, a.module.name)
+	}
+	return nil
+}
+
+// augmentTarget finds the node an absolute schema path of an augment refers to
+func (s *schema) augmentTarget(path string, m *module, selected []string) *node {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	target, _, err := s.split(segments[0], m)
+	if err != nil || !contains(selected, target.name) {
+		return nil
+	}
+	return s.descendant(target.top, path, m)
+}
+
+// root finds a node by a path qualified with module names as in urls, ie
+// /Cisco-IOS-XR-ifmgr-cfg:interface-configurations/interface-configuration
+func (s *schema) root(path string) (*node, error) {
+	var nodes []*node
+	var found *node
+	for i, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		moduleName, name := "", segment
+		if j := strings.Index(segment, ":"); j >= 0 {
+			moduleName, name = segment[:j], segment[j+1:]
+		}
+		if i == 0 {
+			m, ok := s.modules[moduleName]
+			if !ok || m.top == nil {
+				return nil, fmt.Errorf("%s: the first node must be qualified with one of the selected modules", path)
+			}
+			nodes = m.top
+		}
+		if found = findNode(nodes, name, moduleName); found == nil {
+			return nil, fmt.Errorf("%s: no node %s", path, segment)
+		}
+		nodes = found.children
+	}
+	return found, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}